@@ -2,9 +2,11 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 	"github.com/sitehostnz/terraform-provider-sitehost/sitehost"
 )
 
@@ -14,16 +16,25 @@ import (
 var version = "1.0.0"
 
 // main is a function to initialise the Terraform provider.
+// The SDKv2 and framework providers are served together through a mux server.
 func main() {
 	var debugMode bool
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers")
 	flag.Parse()
 
-	opts := &plugin.ServeOpts{
-		Debug:        debugMode,
-		ProviderFunc: sitehost.New(version),
-		ProviderAddr: "sitehostnz/sitehost",
+	ctx := context.Background()
+
+	providerServer, err := sitehost.ProviderServer(ctx, version)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var serveOpts []tf5server.ServeOpt
+	if debugMode {
+		serveOpts = append(serveOpts, tf5server.WithManagedDebug())
 	}
 
-	plugin.Serve(opts)
+	if err := tf5server.Serve("registry.terraform.io/sitehostnz/sitehost", providerServer, serveOpts...); err != nil {
+		log.Fatal(err)
+	}
 }
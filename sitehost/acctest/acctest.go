@@ -0,0 +1,106 @@
+// Package acctest provides what the acceptance tests of the resources share.
+//
+// The tests run Terraform against the in-process fake of the SiteHost API in mockapi, so they don't need a SiteHost account,
+// only a Terraform binary and TF_ACC=1:
+//
+//	TF_ACC=1 go test ./...
+package acctest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+)
+
+const (
+	// ClientID is the client ID of the fake API.
+	ClientID = "12345"
+	// APIKey is the API key of the fake API.
+	APIKey = "test-api-key"
+)
+
+// ProtoV5ProviderFactories serves the provider, as it is served to Terraform, to the tests.
+var ProtoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+	"sitehost": func() (tfprotov5.ProviderServer, error) {
+		providerServer, err := sitehost.ProviderServer(context.Background(), "test")
+		if err != nil {
+			return nil, err
+		}
+
+		return providerServer(), nil
+	},
+}
+
+// NewAPI starts a fake SiteHost API for the test, which is closed when the test finishes.
+func NewAPI(t *testing.T) *mockapi.Server {
+	t.Helper()
+
+	api := mockapi.NewServer(ClientID, APIKey)
+	t.Cleanup(api.Close)
+
+	return api
+}
+
+// ProviderConfig returns the provider block that points the provider at the fake API, for the start of a test config.
+func ProviderConfig(api *mockapi.Server) string {
+	return fmt.Sprintf(`
+provider "sitehost" {
+  client_id    = %q
+  api_key      = %q
+  api_endpoint = %q
+}
+`, ClientID, APIKey, api.Endpoint())
+}
+
+// Client returns the client the checks of a test use to look at the fake API.
+func Client(t *testing.T, api *mockapi.Server) *helper.CombinedConfig {
+	t.Helper()
+
+	config := helper.Config{ClientID: ClientID, APIKey: APIKey, APIEndpoint: api.Endpoint()}
+
+	conf, diags := config.Client(context.Background())
+	if diags.HasError() {
+		t.Fatalf("error creating client: %v", diags)
+	}
+
+	return conf
+}
+
+// CheckDestroy checks that the resources of the type in the state are gone from the fake API,
+// exists is given the state of each resource, a not found error from the API counts as it being gone.
+func CheckDestroy(
+	t *testing.T,
+	api *mockapi.Server,
+	resourceType string,
+	exists func(ctx context.Context, conf *helper.CombinedConfig, rs *terraform.ResourceState) (bool, error),
+) resource.TestCheckFunc {
+	t.Helper()
+
+	return func(s *terraform.State) error {
+		conf := Client(t, api)
+
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			found, err := exists(context.Background(), conf, rs)
+			if err != nil && !helper.IsNotFound(err) {
+				return fmt.Errorf("error checking %s %s was destroyed: %w", resourceType, rs.Primary.ID, err)
+			}
+
+			if err == nil && found {
+				return fmt.Errorf("%s %s still exists", resourceType, rs.Primary.ID)
+			}
+		}
+
+		return nil
+	}
+}
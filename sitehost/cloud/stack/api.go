@@ -0,0 +1,62 @@
+package stack
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/sitehostnz/gosh/pkg/api"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+)
+
+// Updating and deleting a stack, which GoSH doesn't cover, see helper.Post.
+type (
+	// updateRequest represents a request to update the label and docker compose file of a stack.
+	updateRequest struct {
+		ServerName    string `json:"server"`
+		Name          string `json:"name"`
+		Label         string `json:"label"`
+		DockerCompose string `json:"docker_compose"`
+	}
+
+	// deleteRequest represents a request to delete a stack.
+	deleteRequest struct {
+		ServerName string `json:"server"`
+		Name       string `json:"name"`
+	}
+)
+
+// updateStack updates the label and docker compose file of a stack.
+func updateStack(ctx context.Context, client *api.Client, request updateRequest) (response helper.JobResponse, err error) {
+	keys := []string{
+		"server",
+		"name",
+		"params[label]",
+		"params[docker_compose]",
+	}
+
+	values := url.Values{}
+	values.Add("server", request.ServerName)
+	values.Add("name", request.Name)
+	values.Add("params[label]", request.Label)
+	values.Add("params[docker_compose]", request.DockerCompose)
+
+	err = helper.Post(ctx, client, "cloud/stack/update.json", keys, values, &response)
+
+	return response, err
+}
+
+// deleteStack deletes a stack from a server.
+func deleteStack(ctx context.Context, client *api.Client, request deleteRequest) (response helper.JobResponse, err error) {
+	keys := []string{
+		"server",
+		"name",
+	}
+
+	values := url.Values{}
+	values.Add("server", request.ServerName)
+	values.Add("name", request.Name)
+
+	err = helper.Post(ctx, client, "cloud/stack/delete.json", keys, values, &response)
+
+	return response, err
+}
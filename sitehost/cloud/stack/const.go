@@ -0,0 +1,26 @@
+package stack
+
+const (
+	// labelType is the docker label holding the SiteHost container type.
+	labelType = "nz.sitehost.container.type"
+	// labelImageUpdate is the docker label that controls automatic image updates.
+	labelImageUpdate = "nz.sitehost.container.image_update"
+	// labelMonitored is the docker label that controls container monitoring.
+	labelMonitored = "nz.sitehost.container.monitored"
+	// labelBackupDisable is the docker label that disables container backups.
+	labelBackupDisable = "nz.sitehost.container.backup_disable"
+	// labelLabel is the docker label holding the stack label shown in CP.
+	labelLabel = "nz.sitehost.container.label"
+	// labelVirtualHosts is the docker label holding the domains a www container answers to.
+	labelVirtualHosts = "nz.sitehost.container.website.vhosts"
+
+	// environmentVirtualHost is the environment variable the proxy uses to route domains to a container.
+	environmentVirtualHost = "VIRTUAL_HOST"
+
+	// dockerComposeVersion is the compose file version SiteHost generates stacks with.
+	dockerComposeVersion = "2.1"
+	// defaultRestart is the restart policy of a new service that doesn't set one.
+	defaultRestart = "unless-stopped"
+	// defaultNetwork is the external network the SiteHost proxy routes to containers on.
+	defaultNetwork = "infra_default"
+)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/cloud/db/grant"
@@ -20,6 +21,7 @@ func Resource() *schema.Resource {
 		ReadContext:   readResource,
 		UpdateContext: updateResource,
 		DeleteContext: deleteResource,
+		Timeouts:      helper.JobTimeouts(),
 
 		// assume this is correct here.... wheeeee
 		Importer: &schema.ResourceImporter{
@@ -55,6 +57,9 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 		},
 	)
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "database grant")
+		}
 		return diag.Errorf("error retrieving database user: server %s, host %s, database %s, username %s, %s", serverName, mysqlHost, database, username, err)
 	}
 
@@ -69,6 +74,8 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 		if err := d.Set("grants", g.Grants); err != nil {
 			return diag.FromErr(err)
 		}
+	} else {
+		return helper.RemoveFromState(ctx, d, "database grant")
 	}
 
 	return nil
@@ -76,6 +83,8 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 // createResource is a function to create a stack database user.
 func createResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -110,15 +119,19 @@ func createResource(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.Errorf("error retrieving database grants: server %s, host %s, database %s, username %s, %s", serverName, mysqlHost, database, username, err)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Created database grant", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
 // updateResource is a function to update a stack database user.
 func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -128,6 +141,15 @@ func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{
 	serverName := fmt.Sprint(d.Get("server_name"))
 	mysqlHost := fmt.Sprint(d.Get("mysql_host"))
 	username := fmt.Sprint(d.Get("username"))
+	database := fmt.Sprint(d.Get("database"))
+
+	var g []string
+	if grants, ok := d.Get("grants").([]interface{}); ok {
+		g = make([]string, len(grants))
+		for i, v := range grants {
+			g[i] = fmt.Sprint(v)
+		}
+	}
 
 	response, err := client.Update(
 		ctx,
@@ -135,22 +157,27 @@ func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{
 			ServerName: serverName,
 			MySQLHost:  mysqlHost,
 			Username:   username,
-			//			Grants:     d.Get("grants").([]interface{}),
+			Database:   database,
+			Grants:     g,
 		},
 	)
 	if err != nil {
-		return diag.Errorf("error updating database grant: server %s, host %s, username %s, %s", serverName, mysqlHost, username, err)
+		return diag.Errorf("error updating database grant: server %s, host %s, database %s, username %s, %s", serverName, mysqlHost, database, username, err)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Updated database grant", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
 // deleteResource is a function to delete a stack database grant.
 func deleteResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -160,6 +187,7 @@ func deleteResource(ctx context.Context, d *schema.ResourceData, meta interface{
 	serverName := fmt.Sprint(d.Get("server_name"))
 	mysqlHost := fmt.Sprint(d.Get("mysql_host"))
 	username := fmt.Sprint(d.Get("username"))
+	database := fmt.Sprint(d.Get("database"))
 
 	response, err := client.Delete(
 		ctx,
@@ -167,16 +195,19 @@ func deleteResource(ctx context.Context, d *schema.ResourceData, meta interface{
 			ServerName: serverName,
 			MySQLHost:  mysqlHost,
 			Username:   username,
+			Database:   database,
 		},
 	)
 	if err != nil {
-		return diag.Errorf("error removing database user: server %s, host %s, username %s, %s", serverName, mysqlHost, username, err)
+		return diag.Errorf("error removing database grant: server %s, host %s, username %s, database %s, %s", serverName, mysqlHost, username, database, err)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Deleted database grant", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
@@ -0,0 +1,105 @@
+package grant_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sitehostnz/gosh/pkg/api/cloud/db/user"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+)
+
+func TestAccDatabaseGrant(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		// the grants come back with the user, they are gone when the user has none on the database.
+		CheckDestroy: acctest.CheckDestroy(t, api, "sitehost_cloud_database_grant", func(ctx context.Context, conf *helper.CombinedConfig, rs *terraform.ResourceState) (bool, error) {
+			response, err := user.New(conf.Client).Get(ctx, user.GetRequest{
+				ServerName: rs.Primary.Attributes["server_name"],
+				MySQLHost:  rs.Primary.Attributes["mysql_host"],
+				Username:   rs.Primary.Attributes["username"],
+			})
+			if err != nil {
+				return false, err
+			}
+
+			for _, g := range response.User.Grants {
+				if g.DBName == rs.Primary.Attributes["database"] {
+					return true, nil
+				}
+			}
+
+			return false, nil
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseGrantConfig(api, "select"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_cloud_database_grant.test", "database", "app"),
+					resource.TestCheckResourceAttr("sitehost_cloud_database_grant.test", "username", "app"),
+					resource.TestCheckResourceAttr("sitehost_cloud_database_grant.test", "grants.#", "1"),
+					resource.TestCheckResourceAttr("sitehost_cloud_database_grant.test", "grants.0", "select"),
+				),
+			},
+			{
+				Config: testAccDatabaseGrantConfig(api, "select", "insert", "update"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_cloud_database_grant.test", "grants.#", "3"),
+					resource.TestCheckResourceAttr("sitehost_cloud_database_grant.test", "grants.2", "update"),
+				),
+			},
+			{
+				ResourceName:      "sitehost_cloud_database_grant.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccDatabaseGrantConfig(api *mockapi.Server, grants ...string) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_server" "test" {
+  label        = "web"
+  location     = "AKLCITY"
+  product_code = "XENLIT"
+  image        = "ubuntu-jammy-pvh.amd64"
+}
+
+resource "sitehost_stack" "test" {
+  server_name = sitehost_server.test.name
+  name        = "app"
+  label       = "app.example.com"
+  image       = "registry.sitehost.co.nz/sitehost-php83-nginx:5.0.1-noble"
+}
+
+resource "sitehost_cloud_database" "test" {
+  server_name      = sitehost_server.test.name
+  mysql_host       = "mysql8"
+  name             = "app"
+  backup_container = sitehost_stack.test.name
+}
+
+resource "sitehost_cloud_database_user" "test" {
+  server_name = sitehost_server.test.name
+  mysql_host  = "mysql8"
+  username    = "app"
+  password    = "Secret-123456"
+}
+
+resource "sitehost_cloud_database_grant" "test" {
+  server_name = sitehost_server.test.name
+  mysql_host  = "mysql8"
+  database    = sitehost_cloud_database.test.name
+  username    = sitehost_cloud_database_user.test.username
+  grants      = ["%s"]
+}
+`, strings.Join(grants, `", "`))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/cloud/db"
@@ -18,6 +19,7 @@ func Resource() *schema.Resource {
 		ReadContext:   readResource,
 		UpdateContext: updateResource,
 		DeleteContext: deleteResource,
+		Timeouts:      helper.JobTimeouts(),
 
 		// assume this is correct here....
 		Importer: &schema.ResourceImporter{
@@ -50,6 +52,9 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 		},
 	)
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "database")
+		}
 		return diag.Errorf("error retrieving stack: server %s, name %s, database %s, %s", serverName, mysqlHost, database, err)
 	}
 
@@ -62,6 +67,8 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 // createResource is a function to create a stack environment.
 func createResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -88,15 +95,19 @@ func createResource(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.Errorf("error retrieving db: server %s, name %s, database %s, %s", serverName, mysqlHost, database, err)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Created database", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
 // updateResource is a function to update a stack environment.
 func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -121,11 +132,15 @@ func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.Errorf("error updating db: server %s, name %s, database %s, %s", serverName, mysqlHost, database, err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Updated database", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
 // deleteResource is a function to delete a stack environment.
 func deleteResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -148,10 +163,12 @@ func deleteResource(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.Errorf("error removing db: server %s, name %s, database %s, %s", serverName, mysqlHost, database, err)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Deleted database", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
@@ -0,0 +1,81 @@
+package db_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sitehostnz/gosh/pkg/api/cloud/db"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+)
+
+func TestAccDatabase(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		CheckDestroy: acctest.CheckDestroy(t, api, "sitehost_cloud_database", func(ctx context.Context, conf *helper.CombinedConfig, rs *terraform.ResourceState) (bool, error) {
+			_, err := db.New(conf.Client).Get(ctx, db.GetRequest{
+				ServerName: rs.Primary.Attributes["server_name"],
+				MySQLHost:  rs.Primary.Attributes["mysql_host"],
+				Database:   rs.Primary.Attributes["name"],
+			})
+			return err == nil, err
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfig(api, "sitehost_stack.app.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_cloud_database.test", "name", "app"),
+					resource.TestCheckResourceAttr("sitehost_cloud_database.test", "mysql_host", "mysql8"),
+					resource.TestCheckResourceAttr("sitehost_cloud_database.test", "backup_container", "app"),
+				),
+			},
+			{
+				Config: testAccDatabaseConfig(api, "sitehost_stack.backup.name"),
+				Check:  resource.TestCheckResourceAttr("sitehost_cloud_database.test", "backup_container", "backup"),
+			},
+			{
+				ResourceName:      "sitehost_cloud_database.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccDatabaseConfig(api *mockapi.Server, backupContainer string) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_server" "test" {
+  label        = "web"
+  location     = "AKLCITY"
+  product_code = "XENLIT"
+  image        = "ubuntu-jammy-pvh.amd64"
+}
+
+resource "sitehost_stack" "app" {
+  server_name = sitehost_server.test.name
+  name        = "app"
+  label       = "app.example.com"
+  image       = "registry.sitehost.co.nz/sitehost-php83-nginx:5.0.1-noble"
+}
+
+resource "sitehost_stack" "backup" {
+  server_name = sitehost_server.test.name
+  name        = "backup"
+  label       = "backup.example.com"
+  image       = "registry.sitehost.co.nz/sitehost-php83-nginx:5.0.1-noble"
+}
+
+resource "sitehost_cloud_database" "test" {
+  server_name      = sitehost_server.test.name
+  mysql_host       = "mysql8"
+  name             = "app"
+  backup_container = %s
+}
+`, backupContainer)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/cloud/db/user"
@@ -18,6 +19,7 @@ func Resource() *schema.Resource {
 		ReadContext:   readResource,
 		UpdateContext: updateResource,
 		DeleteContext: deleteResource,
+		Timeouts:      helper.JobTimeouts(),
 
 		// assume this is correct here.... wheeeee
 		Importer: &schema.ResourceImporter{
@@ -51,6 +53,9 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 		},
 	)
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "database user")
+		}
 		return diag.Errorf("error retrieving database user: server %s, host %s, username %s, %s", serverName, mysqlHost, username, err)
 	}
 
@@ -59,6 +64,8 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 // createResource is a function to create a stack database user.
 func createResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -87,15 +94,19 @@ func createResource(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.Errorf("error creating database user: server %s, host %s, username %s, %s", serverName, mysqlHost, username, err)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Created database user", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
 // updateResource is a function to update a stack database user.
 func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -120,15 +131,19 @@ func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.Errorf("error updating database user: server %s, host %s, username %s, %s", serverName, mysqlHost, username, err)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Updated database user", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
 // deleteResource is a function to delete a stack database user.
 func deleteResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -151,10 +166,12 @@ func deleteResource(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.Errorf("error removing database user: server %s, host %s, username %s, %s", serverName, mysqlHost, username, err)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Deleted database user", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
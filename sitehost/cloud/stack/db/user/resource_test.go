@@ -0,0 +1,69 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sitehostnz/gosh/pkg/api/cloud/db/user"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+)
+
+func TestAccDatabaseUser(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		CheckDestroy: acctest.CheckDestroy(t, api, "sitehost_cloud_database_user", func(ctx context.Context, conf *helper.CombinedConfig, rs *terraform.ResourceState) (bool, error) {
+			_, err := user.New(conf.Client).Get(ctx, user.GetRequest{
+				ServerName: rs.Primary.Attributes["server_name"],
+				MySQLHost:  rs.Primary.Attributes["mysql_host"],
+				Username:   rs.Primary.Attributes["username"],
+			})
+			return err == nil, err
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseUserConfig(api, "Secret-123456"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_cloud_database_user.test", "username", "app"),
+					resource.TestCheckResourceAttr("sitehost_cloud_database_user.test", "mysql_host", "mysql8"),
+					resource.TestCheckResourceAttr("sitehost_cloud_database_user.test", "password", "Secret-123456"),
+				),
+			},
+			{
+				Config: testAccDatabaseUserConfig(api, "Secret-654321"),
+				Check:  resource.TestCheckResourceAttr("sitehost_cloud_database_user.test", "password", "Secret-654321"),
+			},
+			{
+				ResourceName:      "sitehost_cloud_database_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the API doesn't return the password.
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+
+func testAccDatabaseUserConfig(api *mockapi.Server, password string) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_server" "test" {
+  label        = "web"
+  location     = "AKLCITY"
+  product_code = "XENLIT"
+  image        = "ubuntu-jammy-pvh.amd64"
+}
+
+resource "sitehost_cloud_database_user" "test" {
+  server_name = sitehost_server.test.name
+  mysql_host  = "mysql8"
+  username    = "app"
+  password    = %q
+}
+`, password)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/cloud/stack/environment"
@@ -19,6 +20,7 @@ func Resource() *schema.Resource {
 		ReadContext:   readResource,
 		UpdateContext: updateResource,
 		DeleteContext: deleteResource,
+		Timeouts:      helper.JobTimeouts(),
 
 		// assume this is correct here.... wheeeee
 		Importer: &schema.ResourceImporter{
@@ -54,6 +56,9 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 		environment.GetRequest{ServerName: serverName, Project: project, Service: service},
 	)
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "stack environment")
+		}
 		return diag.Errorf("Error retrieving environment info: %s", err)
 	}
 
@@ -71,6 +76,8 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 // updateResource is a function to update a stack environment. There is no create environment outside creating a stack, these all work on the assumption that the stack exists.
 func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -114,9 +121,11 @@ func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{
 			return diag.Errorf("Error updating environment info: %s", err)
 		}
 
-		if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+		if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 			return diag.FromErr(err)
 		}
+
+		tflog.SubsystemInfo(ctx, helper.LogStack, "Updated stack environment", map[string]any{"id": d.Id(), "changes": len(environmentVariables)})
 	}
 
 	return nil
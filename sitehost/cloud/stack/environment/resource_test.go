@@ -0,0 +1,74 @@
+package environment_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+)
+
+// the environment stays with the stack when it is destroyed, so there is nothing to check it is gone.
+func TestAccStackEnvironment(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStackEnvironmentConfig(api, `
+    APP_ENV = "staging"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_stack_environment.test", "project", "app"),
+					resource.TestCheckResourceAttr("sitehost_stack_environment.test", "service", "app"),
+					resource.TestCheckResourceAttr("sitehost_stack_environment.test", "settings.%", "1"),
+					resource.TestCheckResourceAttr("sitehost_stack_environment.test", "settings.APP_ENV", "staging"),
+				),
+			},
+			{
+				Config: testAccStackEnvironmentConfig(api, `
+    APP_ENV   = "production"
+    APP_DEBUG = "false"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_stack_environment.test", "settings.%", "2"),
+					resource.TestCheckResourceAttr("sitehost_stack_environment.test", "settings.APP_ENV", "production"),
+					resource.TestCheckResourceAttr("sitehost_stack_environment.test", "settings.APP_DEBUG", "false"),
+				),
+			},
+			{
+				ResourceName:      "sitehost_stack_environment.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccStackEnvironmentConfig(api *mockapi.Server, settings string) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_server" "test" {
+  label        = "web"
+  location     = "AKLCITY"
+  product_code = "XENLIT"
+  image        = "ubuntu-jammy-pvh.amd64"
+}
+
+resource "sitehost_stack" "test" {
+  server_name = sitehost_server.test.name
+  name        = "app"
+  label       = "app.example.com"
+  image       = "registry.sitehost.co.nz/sitehost-php83-nginx:5.0.1-noble"
+}
+
+resource "sitehost_stack_environment" "test" {
+  server_name = sitehost_stack.test.server_name
+  project     = sitehost_stack.test.name
+
+  settings = {
+%s  }
+}
+`, settings)
+}
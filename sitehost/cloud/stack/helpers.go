@@ -1,12 +1,17 @@
 package stack
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/models"
 	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"gopkg.in/yaml.v3"
 )
 
 func extractLabelValueFromList(list []string, label string) (ret string) {
@@ -17,13 +22,25 @@ func extractLabelValueFromList(list []string, label string) (ret string) {
 	return ret
 }
 
+// setValueInList sets a `key=value` entry in a compose style list, replacing the existing entry for the key in place.
+func setValueInList(list []string, key, value string) []string {
+	for i, v := range list {
+		if strings.HasPrefix(v, key+"=") {
+			list[i] = key + "=" + value
+			return list
+		}
+	}
+
+	return append(list, key+"="+value)
+}
+
 func extractAliasesFromDockerFile(dockerFile Compose, s models.Stack) []string {
 	var aliases []string
 	for i := range dockerFile.Services[s.Name].Environment {
 		service := dockerFile.Services[s.Name].Environment[i]
-		if strings.HasPrefix(service, "VIRTUAL_HOST=") {
+		if strings.HasPrefix(service, environmentVirtualHost+"=") {
 			aliases = strings.Split(
-				strings.TrimPrefix(service, "VIRTUAL_HOST="),
+				strings.TrimPrefix(service, environmentVirtualHost+"="),
 				",",
 			)
 			aliases = helper.Filter(aliases, func(l string) bool { return l != s.Label })
@@ -33,6 +50,302 @@ func extractAliasesFromDockerFile(dockerFile Compose, s models.Stack) []string {
 	return aliases
 }
 
+// newDockerFile creates the skeleton of a docker compose file for a stack, in the same shape SiteHost generates them.
+func newDockerFile(name string) Compose {
+	return Compose{
+		Version: dockerComposeVersion,
+		Services: map[string]DockerFileService{
+			name: {ContainerName: name},
+		},
+		Networks: map[string]interface{}{
+			"default": map[string]interface{}{
+				"external": map[string]interface{}{
+					"name": defaultNetwork,
+				},
+			},
+		},
+	}
+}
+
+// applyAttributesToDockerFile overlays the structured stack attributes onto the service named after the stack.
+func applyAttributesToDockerFile(dockerFile *Compose, d *schema.ResourceData) error {
+	name := fmt.Sprint(d.Get("name"))
+
+	service, ok := dockerFile.Services[name]
+	if !ok {
+		return fmt.Errorf("the docker_file does not contain a service named %s", name)
+	}
+
+	service.Image = fmt.Sprint(d.Get("image"))
+	service.Restart = fmt.Sprint(d.Get("restart"))
+
+	// these are optional and computed, so only replace what is in the docker file if they are set.
+	if v, ok := d.GetOk("expose"); ok {
+		service.Expose = helper.ToStringList(v)
+	}
+
+	if v, ok := d.GetOk("volumes"); ok {
+		service.Volumes = helper.ToStringList(v)
+	}
+
+	containerType := fmt.Sprint(d.Get("type"))
+	if containerType == "www" {
+		virtualHosts := strings.Join(append([]string{fmt.Sprint(d.Get("label"))}, helper.ToStringList(d.Get("aliases"))...), ",")
+		service.Environment = setValueInList(service.Environment, environmentVirtualHost, virtualHosts)
+		service.Labels = setValueInList(service.Labels, labelVirtualHosts, virtualHosts)
+	}
+
+	service.Labels = setValueInList(service.Labels, labelLabel, fmt.Sprint(d.Get("label")))
+	service.Labels = setValueInList(service.Labels, labelType, containerType)
+	service.Labels = setValueInList(service.Labels, labelImageUpdate, fmt.Sprint(d.Get("image_update")))
+	service.Labels = setValueInList(service.Labels, labelMonitored, fmt.Sprint(d.Get("monitored")))
+	service.Labels = setValueInList(service.Labels, labelBackupDisable, fmt.Sprint(d.Get("backup_disable")))
+
+	dockerFile.Services[name] = service
+
+	return applyServicesToDockerFile(dockerFile, d)
+}
+
+// applyServicesToDockerFile writes the additional services onto the docker compose file.
+// The configured services are authoritative, any other service that isn't the stack's own is removed.
+func applyServicesToDockerFile(dockerFile *Compose, d *schema.ResourceData) error {
+	// leave the services in the docker file alone, unless they are configured.
+	if !helper.IsConfigured(d.GetRawConfig(), "service") {
+		return nil
+	}
+
+	name := fmt.Sprint(d.Get("name"))
+
+	services, ok := d.Get("service").([]interface{})
+	if !ok {
+		return nil
+	}
+
+	configured := map[string]bool{name: true}
+	for _, v := range services {
+		s, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		serviceName := fmt.Sprint(s["name"])
+		if configured[serviceName] {
+			return fmt.Errorf("the service %s is defined more than once, or shares the stack name", serviceName)
+		}
+		configured[serviceName] = true
+
+		service, ok := dockerFile.Services[serviceName]
+		if !ok {
+			service = DockerFileService{ContainerName: serviceName, Restart: defaultRestart}
+		}
+
+		service.Image = fmt.Sprint(s["image"])
+		// the restart policy is computed, so it is left as it is in the docker file unless it is set.
+		if restart, _ := s["restart"].(string); restart != "" {
+			service.Restart = restart
+		}
+		service.Environment = mapToList(s["environment"])
+		service.Labels = mapToList(s["labels"])
+		service.Volumes = helper.ToStringList(s["volumes"])
+
+		dockerFile.Services[serviceName] = service
+	}
+
+	for serviceName := range dockerFile.Services {
+		if !configured[serviceName] {
+			delete(dockerFile.Services, serviceName)
+		}
+	}
+
+	return nil
+}
+
+// extractServicesFromDockerFile reads the additional services, everything but the stack's own service, from the docker compose file.
+// The services named in order come first, in that order, so reordering the services in the docker file doesn't show up as drift,
+// and the rest follow by name.
+func extractServicesFromDockerFile(dockerFile Compose, s models.Stack, order []string) []map[string]interface{} {
+	names := make([]string, 0, len(dockerFile.Services))
+	seen := map[string]bool{s.Name: true}
+	for _, serviceName := range order {
+		if _, ok := dockerFile.Services[serviceName]; ok && !seen[serviceName] {
+			names = append(names, serviceName)
+			seen[serviceName] = true
+		}
+	}
+
+	rest := make([]string, 0, len(dockerFile.Services))
+	for serviceName := range dockerFile.Services {
+		if !seen[serviceName] {
+			rest = append(rest, serviceName)
+		}
+	}
+	sort.Strings(rest)
+	names = append(names, rest...)
+
+	services := make([]map[string]interface{}, 0, len(names))
+	for _, serviceName := range names {
+		service := dockerFile.Services[serviceName]
+		services = append(services, map[string]interface{}{
+			"name":        serviceName,
+			"image":       service.Image,
+			"restart":     service.Restart,
+			"environment": listToMap(service.Environment),
+			"labels":      listToMap(service.Labels),
+			"volumes":     service.Volumes,
+		})
+	}
+
+	return services
+}
+
+// serviceNames returns the names of the service blocks, in order.
+func serviceNames(v interface{}) []string {
+	services, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(services))
+	for _, service := range services {
+		if s, ok := service.(map[string]interface{}); ok {
+			names = append(names, fmt.Sprint(s["name"]))
+		}
+	}
+
+	return names
+}
+
+// listToMap converts a compose style list of `key=value` entries into a map.
+func listToMap(list []string) map[string]string {
+	ret := make(map[string]string, len(list))
+	for _, v := range list {
+		key, value, _ := strings.Cut(v, "=")
+		ret[key] = value
+	}
+
+	return ret
+}
+
+// mapToList converts a terraform map into a compose style list of `key=value` entries, sorted by key.
+func mapToList(m interface{}) []string {
+	values, ok := m.(map[string]interface{})
+	if !ok || len(values) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return helper.Map(keys, func(k string) string { return k + "=" + fmt.Sprint(values[k]) })
+}
+
+// buildDockerFile returns the docker compose file for the stack with the stack attributes applied.
+// When there is no docker_file, one is generated from the attributes.
+func buildDockerFile(d *schema.ResourceData) (string, error) {
+	dockerFile := newDockerFile(fmt.Sprint(d.Get("name")))
+
+	if v, ok := d.GetOk("docker_file"); ok {
+		dockerFile = Compose{}
+		if err := yaml.Unmarshal([]byte(fmt.Sprint(v)), &dockerFile); err != nil {
+			return "", fmt.Errorf("failed to parse docker_file: %w", err)
+		}
+	}
+
+	if err := applyAttributesToDockerFile(&dockerFile, d); err != nil {
+		return "", err
+	}
+
+	return marshalDockerFile(dockerFile)
+}
+
+// dockerFileAttributes are the attributes that end up in the generated docker compose file.
+var dockerFileAttributes = []string{
+	"label",
+	"image",
+	"restart",
+	"expose",
+	"volumes",
+	"aliases",
+	"service",
+	"type",
+	"image_update",
+	"monitored",
+	"backup_disable",
+}
+
+// customizeDiff marks the docker_file as changing when it is generated and the attributes that go into it change.
+func customizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if helper.IsConfigured(d.GetRawConfig(), "docker_file") {
+		return nil
+	}
+
+	if d.Id() != "" && d.HasChanges(dockerFileAttributes...) {
+		return d.SetNewComputed("docker_file")
+	}
+
+	return nil
+}
+
+// marshalDockerFile renders the docker compose file as yaml.
+func marshalDockerFile(dockerFile Compose) (string, error) {
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(dockerFile); err != nil {
+		return "", err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// normaliseDockerFile round trips a docker compose file through the model, so formatting differences are ignored.
+func normaliseDockerFile(content string) (string, error) {
+	dockerFile := Compose{}
+	if err := yaml.Unmarshal([]byte(content), &dockerFile); err != nil {
+		return "", err
+	}
+
+	return marshalDockerFile(dockerFile)
+}
+
+// suppressEquivalentDockerFile suppresses the diff between the docker file from the server and the configured one,
+// if they only differ by the attributes we apply on top or by formatting.
+func suppressEquivalentDockerFile(_, oldValue, newValue string, d *schema.ResourceData) bool {
+	if oldValue == "" || newValue == "" {
+		return false
+	}
+
+	current, err := normaliseDockerFile(oldValue)
+	if err != nil {
+		return false
+	}
+
+	dockerFile := Compose{}
+	if err := yaml.Unmarshal([]byte(newValue), &dockerFile); err != nil {
+		return false
+	}
+
+	if err := applyAttributesToDockerFile(&dockerFile, d); err != nil {
+		return false
+	}
+
+	expected, err := marshalDockerFile(dockerFile)
+	if err != nil {
+		return false
+	}
+
+	return current == expected
+}
+
 // ParseStackName parses a stack identifier into its components: server name, project, and service.
 // Returns a ParsedStackName struct or an error if the ID format is invalid.
 func ParseStackName(id string) (stack *ParsedStackName, err error) {
@@ -0,0 +1,37 @@
+package stack
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sitehostnz/gosh/pkg/models"
+)
+
+func TestExtractServicesFromDockerFileOrder(t *testing.T) {
+	dockerFile := Compose{
+		Services: map[string]DockerFileService{
+			"app":    {Image: "nginx", Restart: "always"},
+			"worker": {Image: "php"},
+			"cron":   {Image: "alpine", Restart: "unless-stopped"},
+			"redis":  {Image: "redis:7"},
+		},
+	}
+
+	// the services named in order keep it, the rest follow by name, and the stack's own service is left out.
+	services := extractServicesFromDockerFile(dockerFile, models.Stack{Name: "app"}, []string{"worker", "gone", "cron"})
+
+	names := make([]string, 0, len(services))
+	for _, service := range services {
+		name, _ := service["name"].(string)
+		names = append(names, name)
+	}
+
+	if want := []string{"worker", "cron", "redis"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got services %v, want %v", names, want)
+	}
+
+	// a service without a restart policy is read back without one, rather than with the default.
+	if restart := services[0]["restart"]; restart != "" {
+		t.Errorf("got restart %q for worker, want none", restart)
+	}
+}
@@ -1,20 +1,29 @@
 // Package stack represents interactions with a stack on sitehose, this is the model.
 package stack
 
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
 type (
 	// DockerFileService represents a DockerFile.
 	DockerFileService struct {
-		Build       string   `yaml:"build,omitempty"`
-		Image       string   `yaml:"image,omitempty"`
-		Command     []string `yaml:"command,omitempty"`
-		Ports       []string `yaml:"ports,omitempty"`
-		Environment []string `yaml:"environment,omitempty"`
-		EnvFile     []string `yaml:"env_file,omitempty"`
-		Restart     string   `yaml:"restart,omitempty"`
-		// looks like a map, but it's an array of things
-		// yaml parser won't treat it as a map
-		Labels  []string `yaml:"labels,omitempty"`
-		Volumes []string `yaml:"volumes,omitempty"`
+		ContainerName string       `yaml:"container_name,omitempty"`
+		Build         string       `yaml:"build,omitempty"`
+		Image         string       `yaml:"image,omitempty"`
+		Command       StringOrList `yaml:"command,omitempty"`
+		Ports         []string     `yaml:"ports,omitempty"`
+		Expose        []string     `yaml:"expose,omitempty"`
+		Environment   ListOrMap    `yaml:"environment,omitempty"`
+		EnvFile       []string     `yaml:"env_file,omitempty"`
+		Restart       string       `yaml:"restart,omitempty"`
+		Labels        ListOrMap    `yaml:"labels,omitempty"`
+		Volumes       []string     `yaml:"volumes,omitempty"`
+
+		// Extra holds everything else in the service definition, so it survives a round trip.
+		Extra map[string]interface{} `yaml:",inline"`
 	}
 
 	// Compose represents a docker Compose.
@@ -22,13 +31,23 @@ type (
 		Version  string                       `yaml:"version"`
 		Services map[string]DockerFileService `yaml:"services"`
 
-		Networks map[string]struct {
-			Driver string `yaml:"driver,omitempty"`
-		} `yaml:"networks,omitempty"`
+		// networks and volumes can carry driver options, external references and so on, keep them as is.
+		Networks map[string]interface{} `yaml:"networks,omitempty"`
+		Volumes  map[string]interface{} `yaml:"volumes,omitempty"`
 
-		Volumes map[string]struct {
-			Driver string `yaml:"driver,omitempty"`
-		} `yaml:"volumes,omitempty"`
+		// Extra holds any other top level keys, so they survive a round trip.
+		Extra map[string]interface{} `yaml:",inline"`
+	}
+
+	// ListOrMap is a compose field, like environment and labels, that is either a list of `key=value` entries or a map.
+	// Either way it is read into a list, which is how it is written back, compose treats the two the same.
+	ListOrMap []string
+
+	// StringOrList is a compose field, like command, that is either a string or a list.
+	// A string is run by a shell and a list is not, so it is written back in the form it was read.
+	StringOrList struct {
+		Values []string
+		String bool
 	}
 
 	// ParsedStackName represents the components of a parsed stack name, it is used primarily in importing so we can handle multiple import formats.
@@ -38,3 +57,71 @@ type (
 		Service    string
 	}
 )
+
+// UnmarshalYAML reads a list, or a map in the order it is written, into `key=value` entries.
+// A map entry without a value is just the key, like it would be in the list.
+func (l *ListOrMap) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.SequenceNode:
+		var list []string
+		if err := value.Decode(&list); err != nil {
+			return err
+		}
+
+		*l = list
+	case yaml.MappingNode:
+		list := make([]string, 0, len(value.Content)/2)
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			key, v := value.Content[i], value.Content[i+1]
+			if v.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: the value of %s must be a string, number or boolean", v.Line, key.Value)
+			}
+
+			if v.Tag == "!!null" {
+				list = append(list, key.Value)
+				continue
+			}
+
+			list = append(list, key.Value+"="+v.Value)
+		}
+
+		*l = list
+	default:
+		return fmt.Errorf("line %d: must be a list or a map", value.Line)
+	}
+
+	return nil
+}
+
+// UnmarshalYAML reads a string or a list.
+func (s *StringOrList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		*s = StringOrList{Values: []string{value.Value}, String: true}
+	case yaml.SequenceNode:
+		var list []string
+		if err := value.Decode(&list); err != nil {
+			return err
+		}
+
+		*s = StringOrList{Values: list}
+	default:
+		return fmt.Errorf("line %d: must be a string or a list", value.Line)
+	}
+
+	return nil
+}
+
+// MarshalYAML writes the string or the list back.
+func (s StringOrList) MarshalYAML() (interface{}, error) {
+	if s.String && len(s.Values) == 1 {
+		return s.Values[0], nil
+	}
+
+	return s.Values, nil
+}
+
+// IsZero reports whether there is nothing to write, for omitempty.
+func (s StringOrList) IsZero() bool {
+	return len(s.Values) == 0
+}
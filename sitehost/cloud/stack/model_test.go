@@ -0,0 +1,60 @@
+package stack
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestNormaliseDockerFileForms(t *testing.T) {
+	content := `version: "2.1"
+services:
+  app:
+    image: nginx
+    command: nginx -g 'daemon off;'
+    environment:
+      VIRTUAL_HOST: example.com
+      WORKERS: 4
+      FROM_SHELL:
+    labels:
+      - nz.sitehost.container.type=www
+  worker:
+    image: php
+    command: ["php", "artisan", "queue:work"]
+    labels:
+      nz.sitehost.container.monitored: true
+`
+
+	normalised, err := normaliseDockerFile(content)
+	if err != nil {
+		t.Fatalf("error normalising docker file: %s", err)
+	}
+
+	dockerFile := Compose{}
+	if err := yaml.Unmarshal([]byte(normalised), &dockerFile); err != nil {
+		t.Fatalf("error parsing normalised docker file: %s\n%s", err, normalised)
+	}
+
+	app, worker := dockerFile.Services["app"], dockerFile.Services["worker"]
+
+	if want := (StringOrList{Values: []string{"nginx -g 'daemon off;'"}, String: true}); !reflect.DeepEqual(app.Command, want) {
+		t.Errorf("got app command %#v, want %#v", app.Command, want)
+	}
+
+	if want := []string{"php", "artisan", "queue:work"}; !reflect.DeepEqual(worker.Command, StringOrList{Values: want}) {
+		t.Errorf("got worker command %#v, want %#v", worker.Command, want)
+	}
+
+	if want := (ListOrMap{"VIRTUAL_HOST=example.com", "WORKERS=4", "FROM_SHELL"}); !reflect.DeepEqual(app.Environment, want) {
+		t.Errorf("got app environment %#v, want %#v", app.Environment, want)
+	}
+
+	if want := (ListOrMap{"nz.sitehost.container.type=www"}); !reflect.DeepEqual(app.Labels, want) {
+		t.Errorf("got app labels %#v, want %#v", app.Labels, want)
+	}
+
+	if want := (ListOrMap{"nz.sitehost.container.monitored=true"}); !reflect.DeepEqual(worker.Labels, want) {
+		t.Errorf("got worker labels %#v, want %#v", worker.Labels, want)
+	}
+}
@@ -5,26 +5,30 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/cloud/stack"
+	"github.com/sitehostnz/gosh/pkg/shtypes"
 	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
 	"gopkg.in/yaml.v3"
 )
 
-// Resource returns a schema with the operations for Server resource.
+// Resource returns a schema with the operations for a stack resource.
 func Resource() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: createResource,
 		ReadContext:   readResource,
 		UpdateContext: updateResource,
 		DeleteContext: deleteResource,
+		Timeouts:      helper.JobTimeouts(),
 
 		// assume this is correct here.... wheeeee
 		Importer: &schema.ResourceImporter{
 			StateContext: importResource,
 		},
-		Schema: resourceSchema,
+		Schema:        resourceSchema,
+		CustomizeDiff: customizeDiff,
 	}
 }
 
@@ -42,6 +46,9 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 	stackClient := stack.New(conf.Client)
 	stackResponse, err := stackClient.Get(ctx, stack.GetRequest{ServerName: serverName, Name: name})
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "stack")
+		}
 		return diag.Errorf("Error retrieving stack info: server %s, stack %s, %s", serverName, name, err)
 	}
 
@@ -72,14 +79,8 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.FromErr(err)
 	}
 
-	// set the docker file here, for fun/read it back...
-	if err := d.Set("docker_file", s.DockerFile); err != nil {
-		return diag.FromErr(err)
-	}
-
-	// the big assumption here... for now... is that we are going to have only one service?
-	// get all the settings from the docker compose that we need...
-	// things that exist in the yaml from the server
+	// the service named after the stack is described by the top level attributes,
+	// any other services in the docker compose file are read into the service blocks.
 
 	// 1. virtual hosts
 	aliases := extractAliasesFromDockerFile(dockerFile, s)
@@ -87,11 +88,31 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.FromErr(err)
 	}
 
-	if err := d.Set("type", extractLabelValueFromList(dockerFile.Services[s.Name].Labels, "nz.sitehost.container.type")); err != nil {
+	if err := d.Set("type", extractLabelValueFromList(dockerFile.Services[s.Name].Labels, labelType)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("image", dockerFile.Services[s.Name].Image); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("restart", dockerFile.Services[s.Name].Restart); err != nil {
 		return diag.FromErr(err)
 	}
 
-	v, err := strconv.ParseBool(extractLabelValueFromList(dockerFile.Services[s.Name].Labels, "nz.sitehost.container.image_update"))
+	if err := d.Set("expose", dockerFile.Services[s.Name].Expose); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("volumes", dockerFile.Services[s.Name].Volumes); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("service", extractServicesFromDockerFile(dockerFile, s, serviceNames(d.Get("service")))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	v, err := strconv.ParseBool(extractLabelValueFromList(dockerFile.Services[s.Name].Labels, labelImageUpdate))
 	if err != nil {
 		v = false
 	}
@@ -101,7 +122,7 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 	}
 
 	// is the stack monitored
-	v, err = strconv.ParseBool(extractLabelValueFromList(dockerFile.Services[s.Name].Labels, "nz.sitehost.container.monitored"))
+	v, err = strconv.ParseBool(extractLabelValueFromList(dockerFile.Services[s.Name].Labels, labelMonitored))
 	if err != nil {
 		v = false
 	}
@@ -111,7 +132,7 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 	}
 
 	// should we disable the backup?
-	v, err = strconv.ParseBool(extractLabelValueFromList(dockerFile.Services[s.Name].Labels, "nz.sitehost.container.backup_disable"))
+	v, err = strconv.ParseBool(extractLabelValueFromList(dockerFile.Services[s.Name].Labels, labelBackupDisable))
 	if err != nil {
 		v = false
 	}
@@ -140,19 +161,131 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 	return nil
 }
 
-// createResource is a function to create a stack environment.
-func createResource(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
-	return diag.Errorf("giving up")
+// createResource is a function to create a stack.
+func createResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	serverName := fmt.Sprint(d.Get("server_name"))
+	name := fmt.Sprint(d.Get("name"))
+
+	dockerFile, err := buildDockerFile(d)
+	if err != nil {
+		return diag.Errorf("Error creating stack: server %s, stack %s, %s", serverName, name, err)
+	}
+
+	enableSSL, ok := d.Get("enable_ssl").(bool)
+	if !ok {
+		return diag.Errorf("failed to convert enable_ssl to bool")
+	}
+
+	client := stack.New(conf.Client)
+	response, err := client.Add(ctx, stack.AddRequest{
+		ServerName:    serverName,
+		Name:          name,
+		Label:         fmt.Sprint(d.Get("label")),
+		EnableSSL:     shtypes.BoolToInt(enableSSL),
+		DockerCompose: dockerFile,
+	})
+	if err != nil {
+		return diag.Errorf("Error creating stack: server %s, stack %s, %s", serverName, name, err)
+	}
+
+	if !response.Status {
+		return diag.Errorf("Error creating stack: server %s, stack %s, %s", serverName, name, response.Msg)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", serverName, name))
+
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Created stack", map[string]any{"id": d.Id()})
+
+	return readResource(ctx, d, meta)
 }
 
-// updateResource is a function to update a stack environment.
-func updateResource(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
-	return diag.Errorf("giving up")
+// updateResource is a function to update a stack, pushing label and docker compose changes.
+func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	serverName := fmt.Sprint(d.Get("server_name"))
+	name := fmt.Sprint(d.Get("name"))
+
+	// ssl can only be turned on when the stack is created, so there is nothing for us to push here.
+	if !d.HasChangesExcept("enable_ssl") {
+		return readResource(ctx, d, meta)
+	}
+
+	dockerFile, err := buildDockerFile(d)
+	if err != nil {
+		return diag.Errorf("Error updating stack: server %s, stack %s, %s", serverName, name, err)
+	}
+
+	response, err := updateStack(ctx, conf.Client, updateRequest{
+		ServerName:    serverName,
+		Name:          name,
+		Label:         fmt.Sprint(d.Get("label")),
+		DockerCompose: dockerFile,
+	})
+	if err != nil {
+		return diag.Errorf("Error updating stack: server %s, stack %s, %s", serverName, name, err)
+	}
+
+	if !response.Status {
+		return diag.Errorf("Error updating stack: server %s, stack %s, %s", serverName, name, response.Msg)
+	}
+
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Updated stack", map[string]any{"id": d.Id()})
+
+	return readResource(ctx, d, meta)
 }
 
-// deleteResource is a function to delete a stack environment.
-func deleteResource(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
-	return diag.Errorf("giving up")
+// deleteResource is a function to delete a stack.
+func deleteResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	serverName := fmt.Sprint(d.Get("server_name"))
+	name := fmt.Sprint(d.Get("name"))
+
+	response, err := deleteStack(ctx, conf.Client, deleteRequest{
+		ServerName: serverName,
+		Name:       name,
+	})
+	if err != nil {
+		return diag.Errorf("Error deleting stack: server %s, stack %s, %s", serverName, name, err)
+	}
+
+	if !response.Status {
+		return diag.Errorf("Error deleting stack: server %s, stack %s, %s", serverName, name, response.Msg)
+	}
+
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Deleted stack", map[string]any{"id": d.Id()})
+
+	return nil
 }
 
 func importResource(_ context.Context, d *schema.ResourceData, _ any) ([]*schema.ResourceData, error) {
@@ -161,7 +294,16 @@ func importResource(_ context.Context, d *schema.ResourceData, _ any) ([]*schema
 		return nil, err
 	}
 
-	d.SetId(fmt.Sprintf("%s/%s/%s", parsedStackName.ServerName, parsedStackName.Project, parsedStackName.Service))
+	// the stack is the project, so it has the same server/name ID whichever form it is imported with.
+	d.SetId(fmt.Sprintf("%s/%s", parsedStackName.ServerName, parsedStackName.Project))
+
+	if err := d.Set("server_name", parsedStackName.ServerName); err != nil {
+		return nil, fmt.Errorf("error importing stack: server %s, stack %s, %s", parsedStackName.ServerName, parsedStackName.Project, err)
+	}
+
+	if err := d.Set("name", parsedStackName.Project); err != nil {
+		return nil, fmt.Errorf("error importing stack: server %s, stack %s, %s", parsedStackName.ServerName, parsedStackName.Project, err)
+	}
 
 	return []*schema.ResourceData{d}, nil
 }
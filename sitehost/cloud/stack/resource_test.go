@@ -0,0 +1,150 @@
+package stack_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sitehostnz/gosh/pkg/api/cloud/stack"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+)
+
+func TestAccStackName(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(api) + `
+resource "sitehost_stack_name" "test" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("sitehost_stack_name.test", "name"),
+					resource.TestCheckResourceAttrPair("sitehost_stack_name.test", "id", "sitehost_stack_name.test", "name"),
+				),
+			},
+			{
+				ResourceName:      "sitehost_stack_name.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccStack(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		CheckDestroy: acctest.CheckDestroy(t, api, "sitehost_stack", func(ctx context.Context, conf *helper.CombinedConfig, rs *terraform.ResourceState) (bool, error) {
+			_, err := stack.New(conf.Client).Get(ctx, stack.GetRequest{ServerName: rs.Primary.Attributes["server_name"], Name: rs.Primary.Attributes["name"]})
+			return err == nil, err
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStackConfig(api, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("sitehost_stack.test", "name", "sitehost_stack_name.test", "name"),
+					resource.TestCheckResourceAttrPair("sitehost_stack.test", "server_name", "sitehost_server.test", "name"),
+					resource.TestCheckResourceAttr("sitehost_stack.test", "label", "app.example.com"),
+					resource.TestCheckResourceAttr("sitehost_stack.test", "restart", "unless-stopped"),
+					resource.TestCheckResourceAttr("sitehost_stack.test", "service.#", "0"),
+					resource.TestCheckResourceAttrSet("sitehost_stack.test", "docker_file"),
+					resource.TestCheckResourceAttrSet("sitehost_stack.test", "server_ip_address"),
+				),
+			},
+			{
+				Config: testAccStackConfig(api, `
+  aliases = ["www.example.com"]
+
+  service {
+    name  = "redis"
+    image = "redis:7"
+
+    environment = {
+      REDIS_ARGS = "--maxmemory 64mb"
+    }
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_stack.test", "aliases.#", "1"),
+					resource.TestCheckResourceAttr("sitehost_stack.test", "aliases.0", "www.example.com"),
+					resource.TestCheckResourceAttr("sitehost_stack.test", "service.#", "1"),
+					resource.TestCheckResourceAttr("sitehost_stack.test", "service.0.name", "redis"),
+					resource.TestCheckResourceAttr("sitehost_stack.test", "service.0.image", "redis:7"),
+					resource.TestCheckResourceAttr("sitehost_stack.test", "service.0.environment.REDIS_ARGS", "--maxmemory 64mb"),
+					resource.TestCheckResourceAttr("sitehost_stack.test", "service.0.restart", "unless-stopped"),
+				),
+			},
+			{
+				// without any service blocks, the services in the docker compose file are left alone.
+				Config: testAccStackConfig(api, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_stack.test", "service.#", "1"),
+					resource.TestCheckResourceAttr("sitehost_stack.test", "service.0.name", "redis"),
+				),
+			},
+			{
+				// they are removed with the docker compose file.
+				Config: testAccStackConfig(api, `
+  docker_file = <<-EOT
+    services:
+      ${sitehost_stack_name.test.name}:
+        image: registry.sitehost.co.nz/sitehost-php83-nginx:5.0.1-noble
+  EOT
+`),
+				Check: resource.TestCheckResourceAttr("sitehost_stack.test", "service.#", "0"),
+			},
+			{
+				ResourceName:      "sitehost_stack.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// the server/project/service form is imported with the same server/name ID.
+				ResourceName:      "sitehost_stack.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccStackServiceImportID("sitehost_stack.test"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccStackServiceImportID returns the server/project/service ID the stack is imported with.
+func testAccStackServiceImportID(name string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("%s not found in state", name)
+		}
+
+		return fmt.Sprintf("%s/%s", rs.Primary.ID, rs.Primary.Attributes["name"]), nil
+	}
+}
+
+func testAccStackConfig(api *mockapi.Server, extra string) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_server" "test" {
+  label        = "web"
+  location     = "AKLCITY"
+  product_code = "XENLIT"
+  image        = "ubuntu-jammy-pvh.amd64"
+}
+
+resource "sitehost_stack_name" "test" {}
+
+resource "sitehost_stack" "test" {
+  server_name = sitehost_server.test.name
+  name        = sitehost_stack_name.test.name
+  label       = "app.example.com"
+  image       = "registry.sitehost.co.nz/sitehost-php83-nginx:5.0.1-noble"
+%s}
+`, extra)
+}
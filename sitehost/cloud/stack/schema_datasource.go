@@ -60,6 +60,11 @@ var stackDataSourceSchema = map[string]*schema.Schema{
 		Type:     schema.TypeString,
 	},
 
+	"restart": {
+		Computed: true,
+		Type:     schema.TypeString,
+	},
+
 	"docker_file": {
 		Computed:    true,
 		Type:        schema.TypeString,
@@ -74,6 +79,57 @@ var stackDataSourceSchema = map[string]*schema.Schema{
 		},
 	},
 
+	"volumes": {
+		Computed: true,
+		Type:     schema.TypeList,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	},
+
+	"service": {
+		Computed:    true,
+		Type:        schema.TypeList,
+		Description: "Additional services in the stack's docker compose project",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"image": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"restart": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"environment": {
+					Type:     schema.TypeMap,
+					Computed: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"labels": {
+					Type:     schema.TypeMap,
+					Computed: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"volumes": {
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	},
+
 	"server_name": {
 		Required:    true,
 		Type:        schema.TypeString,
@@ -10,6 +10,7 @@ var resourceSchema = map[string]*schema.Schema{
 	"name": {
 		Type:        schema.TypeString,
 		Required:    true,
+		ForceNew:    true,
 		Description: "The Stack name",
 	},
 
@@ -31,7 +32,7 @@ var resourceSchema = map[string]*schema.Schema{
 	"monitored": {
 		Type:        schema.TypeBool,
 		Optional:    true,
-		Description: "Enable or disable SSL",
+		Description: "Enable or disable monitoring of the container",
 		Default:     true,
 	},
 	"type": {
@@ -81,19 +82,95 @@ var resourceSchema = map[string]*schema.Schema{
 		Required: true,
 	},
 	"docker_file": {
-		Type:        schema.TypeString,
-		Required:    true,
-		Description: "The docker compose file for the container",
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+		Description: "The docker compose file for the container, it must contain a service named after the stack. " +
+			"The image, restart policy, exposed ports, volumes, aliases and SiteHost labels are applied on top of it from the stack attributes. " +
+			"If it is not set, the docker compose file is generated from the stack attributes",
+		DiffSuppressFunc: suppressEquivalentDockerFile,
 	},
 
 	"expose": {
-		Type:     schema.TypeList,
-		Optional: true,
+		Type:        schema.TypeList,
+		Optional:    true,
+		Computed:    true,
+		Description: "The ports the container exposes to the proxy, e.g. `80/tcp`",
 		Elem: &schema.Schema{
 			Type: schema.TypeString,
 		},
 	},
 
+	"volumes": {
+		Type:        schema.TypeList,
+		Optional:    true,
+		Computed:    true,
+		Description: "The volumes mounted into the container, in the docker compose `source:target[:mode]` format",
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	},
+
+	// additional services that run alongside the stack's own service, e.g. workers and cron.
+	"service": {
+		Type:     schema.TypeList,
+		Optional: true,
+		Computed: true,
+		Description: "Additional services in the stack's docker compose project. The service named after the stack is configured by the top level attributes. " +
+			"When no services are set, the services in the docker compose file are left as they are, so removing every service block doesn't remove them, " +
+			"remove them from `docker_file` instead",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The service name",
+				},
+				"image": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The image the service runs",
+				},
+				"restart": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Computed: true,
+					ValidateFunc: validation.StringInSlice([]string{
+						"always",
+						"unless-stopped",
+						"on-failure",
+						"no",
+					}, false),
+					Description: "The restart policy for the service. When not set, a new service is `unless-stopped` and an existing one keeps the policy in the docker compose file",
+				},
+				"environment": {
+					Type:        schema.TypeMap,
+					Optional:    true,
+					Description: "The environment variables set in the docker compose file for the service",
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"labels": {
+					Type:        schema.TypeMap,
+					Optional:    true,
+					Description: "The docker labels for the service",
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				"volumes": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "The volumes mounted into the service, in the docker compose `source:target[:mode]` format",
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	},
+
 	// server properties can't change these, informational only.
 	"server_name": {
 		Type:        schema.TypeString,
@@ -0,0 +1,47 @@
+package user
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/sitehostnz/gosh/pkg/api"
+	"github.com/sitehostnz/gosh/pkg/api/cloud/ssh/user"
+	"github.com/sitehostnz/gosh/pkg/shtypes"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+)
+
+// updateUser updates an SSH user, GoSH drops read_only_config from the update, see helper.Post.
+func updateUser(ctx context.Context, client *api.Client, request user.UpdateRequest) (response user.UpdateResponse, err error) {
+	keys := []string{
+		"server_name",
+		"username",
+		"params[password]",
+		"params[containers][]",
+		"params[ssh_keys][]",
+		"params[volumes][]",
+		"params[read_only_config]",
+	}
+
+	values := url.Values{}
+	values.Add("server_name", request.ServerName)
+	values.Add("username", request.Username)
+	values.Add("params[password]", request.Password)
+	values.Add("params[read_only_config]", strconv.Itoa(shtypes.BoolToInt(request.ReadOnlyConfig)))
+
+	for _, c := range request.Containers {
+		values.Add("params[containers][]", c)
+	}
+
+	for _, k := range request.SSHKeys {
+		values.Add("params[ssh_keys][]", k)
+	}
+
+	for _, v := range request.Volumes {
+		values.Add("params[volumes][]", v)
+	}
+
+	err = helper.Post(ctx, client, "cloud/ssh/user/update.json", keys, values, &response)
+
+	return response, err
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/cloud/ssh/user"
@@ -18,6 +19,7 @@ func Resource() *schema.Resource {
 		ReadContext:   readResource,
 		UpdateContext: updateResource,
 		DeleteContext: deleteResource,
+		Timeouts:      helper.JobTimeouts(),
 
 		// assume this is correct here.... wheeeee
 		Importer: &schema.ResourceImporter{
@@ -47,6 +49,9 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 		},
 	)
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "ssh user")
+		}
 		return diag.Errorf("error retrieving ssh user: server %s, username %s, %s", serverName, username, err)
 	}
 
@@ -100,6 +105,8 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 // createResource is a function to create a stack environment.
 func createResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -125,8 +132,8 @@ func createResource(ctx context.Context, d *schema.ResourceData, meta interface{
 	}
 
 	if val, ok := d.Get("volume").([]interface{}); ok && val != nil {
+		addRequest.Volumes = make([]string, 0, len(val))
 		for _, v := range val {
-			addRequest.Volumes = make([]string, 0, len(val))
 			if v, ok := v.(map[string]interface{})["name"].(string); ok {
 				addRequest.Volumes = append(addRequest.Volumes, v)
 			}
@@ -142,7 +149,7 @@ func createResource(ctx context.Context, d *schema.ResourceData, meta interface{
 		}
 	}
 
-	if v, ok := d.Get("readonly_config").(bool); ok {
+	if v, ok := d.Get("read_only_config").(bool); ok {
 		addRequest.ReadOnlyConfig = v
 	}
 
@@ -156,15 +163,19 @@ func createResource(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.Errorf("error creating ssh user: server %s, username %s, %s", addRequest.ServerName, addRequest.Username, err)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Created SSH user", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
 // updateResource is a function to update a stack environment.
 func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -186,8 +197,8 @@ func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{
 	}
 
 	if val, ok := d.Get("volume").([]interface{}); ok && val != nil {
+		updateRequest.Volumes = make([]string, 0, len(val))
 		for _, v := range val {
-			updateRequest.Volumes = make([]string, 0, len(val))
 			if v, ok := v.(map[string]interface{})["name"].(string); ok {
 				updateRequest.Volumes = append(updateRequest.Volumes, v)
 			}
@@ -203,25 +214,32 @@ func updateResource(ctx context.Context, d *schema.ResourceData, meta interface{
 		}
 	}
 
+	if v, ok := d.Get("read_only_config").(bool); ok {
+		updateRequest.ReadOnlyConfig = v
+	}
+
 	if err := d.Set("read_only_config", updateRequest.ReadOnlyConfig); err != nil {
 		return diag.FromErr(err)
 	}
 
-	client := user.New(conf.Client)
-	response, err := client.Update(ctx, updateRequest)
+	response, err := updateUser(ctx, conf.Client, updateRequest)
 	if err != nil {
 		return diag.Errorf("error updating ssh user: server %s, username %s, %s", updateRequest.ServerName, updateRequest.Username, err)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Updated SSH user", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
 // deleteResource is a function to delete a stack environment.
 func deleteResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -242,10 +260,12 @@ func deleteResource(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.Errorf("error deleting ssh user: server %s, username %s, %s", serverName, username, err)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Deleted SSH user", map[string]any{"id": d.Id()})
+
 	return nil
 }
 
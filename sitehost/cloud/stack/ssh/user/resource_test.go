@@ -0,0 +1,98 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sitehostnz/gosh/pkg/api/cloud/ssh/user"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+)
+
+func TestAccSSHUser(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		CheckDestroy: acctest.CheckDestroy(t, api, "sitehost_cloud_ssh_user", func(ctx context.Context, conf *helper.CombinedConfig, rs *terraform.ResourceState) (bool, error) {
+			_, err := user.New(conf.Client).Get(ctx, user.GetRequest{
+				ServerName: rs.Primary.Attributes["server_name"],
+				Username:   rs.Primary.Attributes["username"],
+			})
+			return err == nil, err
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSSHUserConfig(api, false, "sitehost_stack.app.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("sitehost_cloud_ssh_user.test", "id"),
+					resource.TestCheckResourceAttr("sitehost_cloud_ssh_user.test", "username", "deploy"),
+					resource.TestCheckResourceAttr("sitehost_cloud_ssh_user.test", "read_only_config", "false"),
+					resource.TestCheckResourceAttr("sitehost_cloud_ssh_user.test", "container.#", "1"),
+					resource.TestCheckResourceAttr("sitehost_cloud_ssh_user.test", "container.0.name", "app"),
+				),
+			},
+			{
+				Config: testAccSSHUserConfig(api, true, "sitehost_stack.app.name", "sitehost_stack.backup.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_cloud_ssh_user.test", "read_only_config", "true"),
+					resource.TestCheckResourceAttr("sitehost_cloud_ssh_user.test", "container.#", "2"),
+					resource.TestCheckResourceAttr("sitehost_cloud_ssh_user.test", "container.1.name", "backup"),
+				),
+			},
+			{
+				ResourceName:      "sitehost_cloud_ssh_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the API doesn't return the password.
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+
+func testAccSSHUserConfig(api *mockapi.Server, readOnlyConfig bool, containers ...string) string {
+	blocks := make([]string, len(containers))
+	for i, container := range containers {
+		blocks[i] = fmt.Sprintf(`
+  container {
+    name = %s
+  }
+`, container)
+	}
+
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_server" "test" {
+  label        = "web"
+  location     = "AKLCITY"
+  product_code = "XENLIT"
+  image        = "ubuntu-jammy-pvh.amd64"
+}
+
+resource "sitehost_stack" "app" {
+  server_name = sitehost_server.test.name
+  name        = "app"
+  label       = "app.example.com"
+  image       = "registry.sitehost.co.nz/sitehost-php83-nginx:5.0.1-noble"
+}
+
+resource "sitehost_stack" "backup" {
+  server_name = sitehost_server.test.name
+  name        = "backup"
+  label       = "backup.example.com"
+  image       = "registry.sitehost.co.nz/sitehost-php83-nginx:5.0.1-noble"
+}
+
+resource "sitehost_cloud_ssh_user" "test" {
+  server_name      = sitehost_server.test.name
+  username         = "deploy"
+  password         = "Secret-123456"
+  read_only_config = %t
+%s}
+`, readOnlyConfig, strings.Join(blocks, ""))
+}
@@ -3,6 +3,7 @@ package stack
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/cloud/stack"
@@ -43,6 +44,8 @@ func readStackNameResource(_ context.Context, d *schema.ResourceData, _ interfac
 }
 
 func createStackNameResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogStack)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -59,6 +62,8 @@ func createStackNameResource(ctx context.Context, d *schema.ResourceData, meta i
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogStack, "Generated stack name", map[string]any{"name": d.Id()})
+
 	return nil
 }
 
@@ -0,0 +1,68 @@
+package dns
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/sitehostnz/gosh/pkg/api"
+	"github.com/sitehostnz/gosh/pkg/api/dns"
+	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+)
+
+// Adding and updating a record with its TTL, which GoSH doesn't send, see helper.Post.
+type (
+	// recordRequest represents a request to add or update a DNS record.
+	recordRequest struct {
+		Domain   string `json:"domain"`
+		RecordID string `json:"record_id"`
+		Type     string `json:"type"`
+		Name     string `json:"name"`
+		Content  string `json:"content"`
+		Priority string `json:"prio"`
+		TTL      string `json:"ttl"`
+	}
+)
+
+// recordKeys are the keys of a record request, in order.
+var recordKeys = []string{
+	"domain",
+	"record_id",
+	"type",
+	"name",
+	"content",
+	"prio",
+	"ttl",
+}
+
+// recordValues builds the form values for a record request, the record ID and TTL are only sent when they are set.
+func recordValues(request recordRequest) url.Values {
+	values := url.Values{}
+	values.Add("domain", request.Domain)
+	if request.RecordID != "" {
+		values.Add("record_id", request.RecordID)
+	}
+	values.Add("type", request.Type)
+	values.Add("name", request.Name)
+	values.Add("content", request.Content)
+	values.Add("prio", request.Priority)
+	if request.TTL != "" {
+		values.Add("ttl", request.TTL)
+	}
+
+	return values
+}
+
+// addRecord adds a new record to the DNS for a domain.
+func addRecord(ctx context.Context, client *api.Client, request recordRequest) (response dns.AddRecordResponse, err error) {
+	err = helper.Post(ctx, client, "dns/add_record.json", recordKeys, recordValues(request), &response)
+
+	return response, err
+}
+
+// updateRecord updates an existing DNS record for a domain.
+func updateRecord(ctx context.Context, client *api.Client, request recordRequest) (response models.APIResponse, err error) {
+	err = helper.Post(ctx, client, "dns/update_record.json", recordKeys, recordValues(request), &response)
+
+	return response, err
+}
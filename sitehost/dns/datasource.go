@@ -0,0 +1,189 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sitehostnz/gosh/pkg/api/dns"
+	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+)
+
+// ZonesDataSource returns a schema with the function to list the DNS Zones on the account.
+func ZonesDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readZonesDataSource,
+		Schema:      zonesDataSourceSchema,
+	}
+}
+
+// ZoneDataSource returns a schema with the function to read a DNS Zone and its records.
+func ZoneDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readZoneDataSource,
+		Schema:      zoneDataSourceSchema,
+	}
+}
+
+// RecordsDataSource returns a schema with the function to look up DNS Records, with/without a filter.
+func RecordsDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readRecordsDataSource,
+		Schema:      recordsDataSourceSchema,
+	}
+}
+
+// ZoneExportDataSource returns a schema with the function to export a DNS Zone.
+func ZoneExportDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readZoneExportDataSource,
+		Schema:      zoneExportDataSourceSchema,
+	}
+}
+
+// readZonesDataSource is a function to list every DNS Zone on the account.
+func readZonesDataSource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	client := dns.New(conf.Client)
+
+	zones := []map[string]interface{}{}
+	for page := 1; ; page++ {
+		response, err := client.ListZones(ctx, &dns.ListZoneOptions{PageNumber: page})
+		if err != nil {
+			return diag.Errorf("Error retrieving domains: %s", err)
+		}
+
+		if !response.Status {
+			return diag.Errorf("Error retrieving domains: %s", response.Msg)
+		}
+
+		for _, zone := range response.Return.Data {
+			zones = append(zones, map[string]interface{}{
+				"name": zone.Name,
+			})
+		}
+
+		if page >= response.Return.TotalPages {
+			break
+		}
+	}
+
+	d.SetId("zones")
+
+	if err := d.Set("zones", zones); err != nil {
+		return diag.Errorf("Error retrieving domains: %s", err)
+	}
+
+	return nil
+}
+
+// readZoneDataSource is a function to read a DNS Zone and its records.
+func readZoneDataSource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	domain := fmt.Sprintf("%v", d.Get("name"))
+
+	client := dns.New(conf.Client)
+	response, err := client.GetZone(ctx, dns.GetZoneRequest{DomainName: domain})
+	if err != nil {
+		return diag.Errorf("Error retrieving domain: %s", err)
+	}
+
+	if !response.Status {
+		return diag.Errorf("Error retrieving domain: %s", response.Msg)
+	}
+
+	if !hasZone(response, domain) {
+		return diag.Errorf("Error retrieving domain: %s not found", domain)
+	}
+
+	records, err := listRecords(ctx, client, domain)
+	if err != nil {
+		return diag.Errorf("Error retrieving DNS records: %s", err)
+	}
+
+	d.SetId(domain)
+
+	if err := d.Set("records", flattenDataSourceRecords(records, domain)); err != nil {
+		return diag.Errorf("Error retrieving DNS records: %s", err)
+	}
+
+	return nil
+}
+
+// readRecordsDataSource is a function to look up the DNS Records in a zone, filtered by name and type.
+func readRecordsDataSource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	domain := fmt.Sprintf("%v", d.Get("domain"))
+	name, filterName := d.GetOk("name")
+	recordType, filterType := d.GetOk("type")
+
+	client := dns.New(conf.Client)
+	records, err := listRecords(ctx, client, domain)
+	if err != nil {
+		return diag.Errorf("Error retrieving DNS records: %s", err)
+	}
+
+	matches := make([]models.DNSRecord, 0, len(records))
+	for _, record := range records {
+		if filterName && recordFqdn(fmt.Sprintf("%v", name), domain) != recordFqdn(record.Name, domain) {
+			continue
+		}
+
+		if filterType && !strings.EqualFold(fmt.Sprintf("%v", recordType), record.Type) {
+			continue
+		}
+
+		matches = append(matches, record)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", domain, d.Get("name"), d.Get("type")))
+
+	if err := d.Set("records", flattenDataSourceRecords(matches, domain)); err != nil {
+		return diag.Errorf("Error retrieving DNS records: %s", err)
+	}
+
+	return nil
+}
+
+// readZoneExportDataSource is a function to read every record of a DNS Zone and render it as a zone file.
+func readZoneExportDataSource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	domain := fmt.Sprintf("%v", d.Get("domain"))
+
+	client := dns.New(conf.Client)
+	records, err := listRecords(ctx, client, domain)
+	if err != nil {
+		return diag.Errorf("Error retrieving DNS records: %s", err)
+	}
+
+	d.SetId(domain)
+
+	if err := d.Set("zone_file", renderZoneFile(domain, records)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("records", flattenDataSourceRecords(records, domain)); err != nil {
+		return diag.Errorf("Error retrieving DNS records: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,473 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sitehostnz/gosh/pkg/api"
+	"github.com/sitehostnz/gosh/pkg/api/dns"
+	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/gosh/pkg/net"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+)
+
+// identity returns what identifies a record, two records with the same identity are the same record, whatever their TTL.
+func (r zoneRecord) identity() string {
+	return fmt.Sprintf("%s|%s|%s|%d", strings.ToLower(r.Name), strings.ToUpper(r.Type), strings.TrimSuffix(r.Content, "."), r.Priority)
+}
+
+// key returns the identity of a record with its TTL, two records with the same key are the same, TTL and all.
+func (r zoneRecord) key() string {
+	return fmt.Sprintf("%s|%d", r.identity(), r.TTL)
+}
+
+// matchesRecords checks if the records have the records that are wanted, and nothing else.
+// A wanted record without a TTL matches whatever TTL SiteHost gave it.
+func matchesRecords(want, have []zoneRecord) bool {
+	if len(want) != len(have) {
+		return false
+	}
+
+	keys := make(map[string]bool, len(have))
+	identities := make(map[string]bool, len(have))
+	for _, r := range have {
+		keys[r.key()] = true
+		identities[r.identity()] = true
+	}
+
+	for _, r := range want {
+		if r.TTL == 0 && !identities[r.identity()] || r.TTL != 0 && !keys[r.key()] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasPriority checks if the priority is meaningful for the record type.
+func hasPriority(recordType string) bool {
+	switch strings.ToUpper(recordType) {
+	case "MX", "SRV":
+		return true
+	default:
+		return false
+	}
+}
+
+// recordFqdn returns the fully qualified name of a record, the name can be relative to the domain
+// or fully qualified without the trailing dot, as SiteHost returns it.
+func recordFqdn(name, domain string) string {
+	name = strings.ToLower(name)
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	if name == domain || strings.HasSuffix(name, "."+domain) {
+		name += "."
+	}
+
+	return net.ConstructFqdn(name, domain)
+}
+
+// hasZone reports whether the domain search found the domain itself, the search also matches other domains.
+func hasZone(response dns.GetZoneResponse, domain string) bool {
+	for _, zone := range response.Return {
+		if strings.EqualFold(strings.TrimSuffix(zone.Name, "."), strings.TrimSuffix(domain, ".")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newZoneRecord converts a SiteHost record into a zoneRecord.
+func newZoneRecord(record models.DNSRecord, domain string) zoneRecord {
+	r := zoneRecord{
+		Name:    net.DeconstructFqdn(record.Name, domain),
+		Type:    strings.ToUpper(record.Type),
+		Content: strings.TrimSuffix(record.Content, "."),
+	}
+
+	if hasPriority(r.Type) {
+		r.Priority, _ = strconv.Atoi(record.Priority)
+	}
+
+	r.TTL, _ = strconv.Atoi(record.TTL)
+
+	return r
+}
+
+// zoneRecordFromMap converts a record block into a zoneRecord.
+func zoneRecordFromMap(m map[string]interface{}) zoneRecord {
+	r := zoneRecord{
+		Name:    strings.ToLower(fmt.Sprint(m["name"])),
+		Type:    strings.ToUpper(fmt.Sprint(m["type"])),
+		Content: strings.TrimSuffix(fmt.Sprint(m["content"]), "."),
+	}
+
+	if priority, ok := m["priority"].(int); ok && hasPriority(r.Type) {
+		r.Priority = priority
+	}
+
+	if ttl, ok := m["ttl"].(int); ok {
+		r.TTL = ttl
+	}
+
+	return r
+}
+
+// hashZoneRecord is the set hash for record blocks.
+func hashZoneRecord(v interface{}) int {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+
+	return schema.HashString(zoneRecordFromMap(m).key())
+}
+
+// expandZoneRecords converts the record blocks into zoneRecords.
+func expandZoneRecords(v interface{}) []zoneRecord {
+	set, ok := v.(*schema.Set)
+	if !ok {
+		return nil
+	}
+
+	records := make([]zoneRecord, 0, set.Len())
+	for _, r := range set.List() {
+		if m, ok := r.(map[string]interface{}); ok {
+			records = append(records, zoneRecordFromMap(m))
+		}
+	}
+
+	return records
+}
+
+// withoutDefaultTTLs drops the TTL of the records that weren't given one, so the default SiteHost picks isn't a change.
+func withoutDefaultTTLs(records, configured []zoneRecord) []zoneRecord {
+	hasTTL := make(map[string]bool, len(configured))
+	for _, r := range configured {
+		hasTTL[r.identity()] = r.TTL != 0
+	}
+
+	ret := make([]zoneRecord, len(records))
+	for i, r := range records {
+		if !hasTTL[r.identity()] {
+			r.TTL = 0
+		}
+		ret[i] = r
+	}
+
+	return ret
+}
+
+// flattenZoneRecords converts zoneRecords into record blocks.
+func flattenZoneRecords(records []zoneRecord) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, len(records))
+	for _, r := range records {
+		ret = append(ret, map[string]interface{}{
+			"name":     r.Name,
+			"type":     r.Type,
+			"content":  r.Content,
+			"priority": r.Priority,
+			"ttl":      r.TTL,
+		})
+	}
+
+	return ret
+}
+
+// isManagedRecordType checks if a record type is one we manage, the SOA belongs to SiteHost.
+func isManagedRecordType(recordType string) bool {
+	return !strings.EqualFold(recordType, "SOA")
+}
+
+// listRecords lists every record in a zone, including the SOA.
+func listRecords(ctx context.Context, client *dns.Client, domain string) ([]models.DNSRecord, error) {
+	response, err := client.ListRecords(ctx, dns.ListRecordsRequest{Domain: domain})
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Status {
+		return nil, fmt.Errorf("%s", response.Msg)
+	}
+
+	return response.Return, nil
+}
+
+// listZoneRecords lists the records in a zone that we can manage.
+func listZoneRecords(ctx context.Context, client *dns.Client, domain string) ([]models.DNSRecord, error) {
+	all, err := listRecords(ctx, client, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]models.DNSRecord, 0, len(all))
+	for _, record := range all {
+		if isManagedRecordType(record.Type) {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+// reconcileZoneRecords makes the records in the zone match the wanted records.
+// New records are added before old ones are removed, so names don't stop resolving part way through.
+// A record that is already there only changes if its TTL is set and differs.
+func reconcileZoneRecords(ctx context.Context, client *api.Client, domain string, want []zoneRecord) error {
+	current, err := listZoneRecords(ctx, dns.New(client), domain)
+	if err != nil {
+		return fmt.Errorf("error listing records for %s: %w", domain, err)
+	}
+
+	existing := make(map[string]models.DNSRecord, len(current))
+	for _, record := range current {
+		existing[newZoneRecord(record, domain).identity()] = record
+	}
+
+	wanted := make(map[string]bool, len(want))
+	for _, r := range want {
+		wanted[r.identity()] = true
+
+		request := recordRequest{
+			Domain:   domain,
+			Type:     r.Type,
+			Name:     strings.TrimSuffix(net.ConstructFqdn(r.Name, domain), "."),
+			Content:  r.Content,
+			Priority: strconv.Itoa(r.Priority),
+		}
+		if r.TTL != 0 {
+			request.TTL = strconv.Itoa(r.TTL)
+		}
+
+		if record, ok := existing[r.identity()]; ok {
+			if r.TTL == 0 || newZoneRecord(record, domain).TTL == r.TTL {
+				continue
+			}
+
+			request.RecordID = record.ID
+			resp, err := updateRecord(ctx, client, request)
+			if err != nil {
+				return fmt.Errorf("error updating %s record %s in %s: %w", r.Type, r.Name, domain, err)
+			}
+
+			if !resp.Status {
+				return fmt.Errorf("error updating %s record %s in %s: %s", r.Type, r.Name, domain, resp.Msg)
+			}
+
+			tflog.SubsystemDebug(ctx, helper.LogDNS, "Updated DNS record TTL", map[string]any{"domain": domain, "name": r.Name, "type": r.Type, "ttl": r.TTL})
+
+			continue
+		}
+
+		resp, err := addRecord(ctx, client, request)
+		if err != nil {
+			return fmt.Errorf("error adding %s record %s to %s: %w", r.Type, r.Name, domain, err)
+		}
+
+		if !resp.Status {
+			return fmt.Errorf("error adding %s record %s to %s: %s", r.Type, r.Name, domain, resp.Msg)
+		}
+
+		tflog.SubsystemDebug(ctx, helper.LogDNS, "Added DNS record", map[string]any{"domain": domain, "name": r.Name, "type": r.Type})
+
+		// guard against duplicates in the wanted records.
+		existing[r.identity()] = models.DNSRecord{}
+	}
+
+	for _, record := range current {
+		r := newZoneRecord(record, domain)
+		if wanted[r.identity()] {
+			continue
+		}
+
+		resp, err := dns.New(client).DeleteRecord(ctx, dns.DeleteRecordRequest{
+			Domain:   domain,
+			RecordID: record.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("error removing %s record %s from %s: %w", record.Type, record.Name, domain, err)
+		}
+
+		if !resp.Status {
+			return fmt.Errorf("error removing %s record %s from %s: %s", record.Type, record.Name, domain, resp.Msg)
+		}
+
+		tflog.SubsystemDebug(ctx, helper.LogDNS, "Removed DNS record", map[string]any{"domain": domain, "name": r.Name, "type": r.Type})
+	}
+
+	return nil
+}
+
+// flattenDataSourceRecords converts SiteHost records into the records of a data source.
+func flattenDataSourceRecords(records []models.DNSRecord, domain string) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		r := newZoneRecord(record, domain)
+		ttl, _ := strconv.Atoi(record.TTL)
+
+		ret = append(ret, map[string]interface{}{
+			"id":       record.ID,
+			"name":     r.Name,
+			"fqdn":     strings.TrimSuffix(record.Name, "."),
+			"type":     r.Type,
+			"content":  r.Content,
+			"priority": r.Priority,
+			"ttl":      ttl,
+		})
+	}
+
+	return ret
+}
+
+// recordBlocks are the typed blocks that can be used for the record content, keyed by record type.
+var recordBlocks = map[string]string{
+	"SRV": "srv",
+	"CAA": "caa",
+	"MX":  "mx",
+}
+
+// configuredBlock returns the typed record block in the configuration, if there is one.
+func configuredBlock(d recordGetter) (string, map[string]interface{}) {
+	config := d.GetRawConfig()
+	for _, key := range recordBlocks {
+		if !helper.IsConfigured(config, key) {
+			continue
+		}
+
+		blocks, ok := d.Get(key).([]interface{})
+		if !ok || len(blocks) == 0 {
+			return key, nil
+		}
+
+		block, ok := blocks[0].(map[string]interface{})
+		if !ok {
+			return key, nil
+		}
+
+		return key, block
+	}
+
+	return "", nil
+}
+
+// recordContent returns the SiteHost content and priority of a record, serialising the typed block when there is one.
+func recordContent(d recordGetter) (string, int) {
+	priority, _ := d.Get("priority").(int)
+
+	key, block := configuredBlock(d)
+	if block == nil {
+		return fmt.Sprintf("%v", d.Get("record")), priority
+	}
+
+	switch key {
+	case "srv":
+		return fmt.Sprintf("%v %v %s", block["weight"], block["port"], strings.TrimSuffix(fmt.Sprint(block["target"]), ".")), priority
+	case "caa":
+		return fmt.Sprintf("%v %v \"%v\"", block["flags"], block["tag"], block["value"]), 0
+	case "mx":
+		preference, _ := block["preference"].(int)
+		return strings.TrimSuffix(fmt.Sprint(block["exchange"]), "."), preference
+	default:
+		return fmt.Sprintf("%v", d.Get("record")), priority
+	}
+}
+
+// flattenRecordBlock parses the SiteHost content of a record back into its typed block.
+// Records without a typed block, or with content that does not parse, get an empty block.
+func flattenRecordBlock(recordType, content string, priority int) []map[string]interface{} {
+	fields := strings.Fields(content)
+
+	switch strings.ToUpper(recordType) {
+	case "SRV":
+		if len(fields) != 3 {
+			return nil
+		}
+
+		weight, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil
+		}
+
+		port, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil
+		}
+
+		return []map[string]interface{}{{
+			"weight": weight,
+			"port":   port,
+			"target": strings.TrimSuffix(fields[2], "."),
+		}}
+	case "CAA":
+		if len(fields) < 3 {
+			return nil
+		}
+
+		flags, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil
+		}
+
+		// the value is quoted, and can have spaces in it.
+		value := strings.TrimSpace(strings.SplitN(content, fields[1], 2)[1])
+
+		return []map[string]interface{}{{
+			"flags": flags,
+			"tag":   strings.ToLower(fields[1]),
+			"value": strings.Trim(value, "\""),
+		}}
+	case "MX":
+		if len(fields) != 1 {
+			return nil
+		}
+
+		return []map[string]interface{}{{
+			"preference": priority,
+			"exchange":   strings.TrimSuffix(fields[0], "."),
+		}}
+	default:
+		return nil
+	}
+}
+
+// customizeRecordDiff checks a typed block matches the record type, and plans the content it serialises to.
+func customizeRecordDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("type") {
+		return nil
+	}
+
+	recordType := strings.ToUpper(fmt.Sprintf("%v", d.Get("type")))
+
+	key, block := configuredBlock(d)
+	if key == "" {
+		return nil
+	}
+
+	if recordBlocks[recordType] != key {
+		return fmt.Errorf("the %s block can only be used with %s records", key, strings.ToUpper(key))
+	}
+
+	// the block may still have unknown values in it, the content is worked out when applying.
+	if block == nil || !d.NewValueKnown(key) {
+		return nil
+	}
+
+	content, priority := recordContent(d)
+
+	if old, _ := d.GetChange("record"); strings.TrimSuffix(fmt.Sprint(old), ".") != content {
+		if err := d.SetNew("record", content); err != nil {
+			return err
+		}
+	}
+
+	if old, _ := d.GetChange("priority"); old != priority {
+		return d.SetNew("priority", priority)
+	}
+
+	return nil
+}
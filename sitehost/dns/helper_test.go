@@ -0,0 +1,173 @@
+package dns
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/sitehostnz/gosh/pkg/api/dns"
+)
+
+// blockConfig is a recordGetter with a single typed block configured, and the record priority.
+type blockConfig struct {
+	key      string
+	block    map[string]interface{}
+	priority int
+}
+
+func (c blockConfig) Get(key string) interface{} {
+	switch key {
+	case c.key:
+		return []interface{}{c.block}
+	case "priority":
+		return c.priority
+	default:
+		return nil
+	}
+}
+
+func (c blockConfig) GetRawConfig() cty.Value {
+	return cty.ObjectVal(map[string]cty.Value{c.key: cty.True})
+}
+
+func TestRecordFqdn(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "www", want: "www.example.com."},
+		{name: "@", want: "example.com."},
+		{name: "WWW", want: "www.example.com."},
+		{name: "www.example.com", want: "www.example.com."},
+		{name: "www.example.com.", want: "www.example.com."},
+		{name: "example.com", want: "example.com."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recordFqdn(tt.name, "example.com"); got != tt.want {
+				t.Errorf("recordFqdn(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasZone(t *testing.T) {
+	var response dns.GetZoneResponse
+	response.Return = append(response.Return, struct {
+		Name string `json:"name"`
+	}{Name: "myexample.com"})
+
+	if hasZone(response, "example.com") {
+		t.Error("hasZone matched myexample.com for example.com")
+	}
+
+	response.Return[0].Name = "Example.com"
+	if !hasZone(response, "example.com.") {
+		t.Error("hasZone did not match Example.com for example.com.")
+	}
+}
+
+func TestRecordBlockRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType string
+		content    string
+		priority   int
+		want       map[string]interface{}
+		// wantContent is the content the block serialises back to, when it is not the content itself.
+		wantContent string
+	}{
+		{
+			name:       "SRV",
+			recordType: "SRV",
+			content:    "20 5060 sip.example.com",
+			priority:   10,
+			want:       map[string]interface{}{"weight": 20, "port": 5060, "target": "sip.example.com"},
+		},
+		{
+			name:        "SRV with a fully qualified target",
+			recordType:  "srv",
+			content:     "0 443 example.com.",
+			want:        map[string]interface{}{"weight": 0, "port": 443, "target": "example.com"},
+			wantContent: "0 443 example.com",
+		},
+		{
+			name:       "CAA",
+			recordType: "CAA",
+			content:    `0 issue "letsencrypt.org"`,
+			want:       map[string]interface{}{"flags": 0, "tag": "issue", "value": "letsencrypt.org"},
+		},
+		{
+			name:       "CAA critical iodef",
+			recordType: "CAA",
+			content:    `128 iodef "mailto:security@example.com"`,
+			want:       map[string]interface{}{"flags": 128, "tag": "iodef", "value": "mailto:security@example.com"},
+		},
+		{
+			name:       "CAA value with spaces",
+			recordType: "CAA",
+			content:    `0 issuewild "letsencrypt.org; validationmethods=dns-01"`,
+			want:       map[string]interface{}{"flags": 0, "tag": "issuewild", "value": "letsencrypt.org; validationmethods=dns-01"},
+		},
+		{
+			name:        "CAA upper case tag",
+			recordType:  "CAA",
+			content:     `0 ISSUE "letsencrypt.org"`,
+			want:        map[string]interface{}{"flags": 0, "tag": "issue", "value": "letsencrypt.org"},
+			wantContent: `0 issue "letsencrypt.org"`,
+		},
+		{
+			name:       "MX",
+			recordType: "MX",
+			content:    "mail.example.com",
+			priority:   10,
+			want:       map[string]interface{}{"preference": 10, "exchange": "mail.example.com"},
+		},
+		{
+			name:        "MX with a fully qualified exchange",
+			recordType:  "MX",
+			content:     "mail.example.com.",
+			want:        map[string]interface{}{"preference": 0, "exchange": "mail.example.com"},
+			wantContent: "mail.example.com",
+		},
+		{name: "SRV missing the target", recordType: "SRV", content: "20 5060"},
+		{name: "SRV with a named port", recordType: "SRV", content: "20 sip sip.example.com"},
+		{name: "CAA with named flags", recordType: "CAA", content: `critical issue "letsencrypt.org"`},
+		{name: "MX with a preference", recordType: "MX", content: "10 mail.example.com"},
+		{name: "no typed block", recordType: "A", content: "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := flattenRecordBlock(tt.recordType, tt.content, tt.priority)
+			if tt.want == nil {
+				if blocks != nil {
+					t.Fatalf("flattenRecordBlock() = %v, want nil", blocks)
+				}
+				return
+			}
+
+			if len(blocks) != 1 || !reflect.DeepEqual(blocks[0], tt.want) {
+				t.Fatalf("flattenRecordBlock() = %v, want %v", blocks, tt.want)
+			}
+
+			wantContent := tt.content
+			if tt.wantContent != "" {
+				wantContent = tt.wantContent
+			}
+
+			wantPriority := tt.priority
+			if tt.recordType == "CAA" {
+				wantPriority = 0
+			}
+
+			config := blockConfig{key: recordBlocks[strings.ToUpper(tt.recordType)], block: blocks[0], priority: tt.priority}
+			content, priority := recordContent(config)
+			if content != wantContent || priority != wantPriority {
+				t.Errorf("recordContent() = %q, %d, want %q, %d", content, priority, wantContent, wantPriority)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package dns
+
+import "github.com/hashicorp/go-cty/cty"
+
+type (
+	// recordGetter is satisfied by both schema.ResourceData and schema.ResourceDiff,
+	// so the record content can be built the same way at plan time and when applying.
+	recordGetter interface {
+		Get(key string) interface{}
+		GetRawConfig() cty.Value
+	}
+
+	// zoneRecord is the comparable form of a DNS record, used to reconcile the records in a zone.
+	// The name is relative to the domain, with `@` for the apex, and a TTL of 0 leaves it to SiteHost.
+	zoneRecord struct {
+		Name     string
+		Type     string
+		Content  string
+		Priority int
+		TTL      int
+	}
+)
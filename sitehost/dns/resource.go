@@ -4,10 +4,10 @@ package dns
 import (
 	"context"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/dns"
@@ -21,6 +21,7 @@ func ZoneResource() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: createZoneResource,
 		ReadContext:   readZoneResource,
+		UpdateContext: updateZoneResource,
 		DeleteContext: deleteZoneResource,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -31,6 +32,8 @@ func ZoneResource() *schema.Resource {
 
 // createZoneResource is a function to create a new DNS Zone.
 func createZoneResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogDNS)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -51,9 +54,15 @@ func createZoneResource(ctx context.Context, d *schema.ResourceData, meta interf
 
 	d.SetId(domain)
 
-	log.Printf("[INFO] Domain Name: %s", d.Id())
+	tflog.SubsystemInfo(ctx, helper.LogDNS, "Created DNS zone", map[string]any{"domain": d.Id()})
 
-	return nil
+	if records := expandZoneRecords(d.Get("record")); len(records) > 0 {
+		if err := reconcileZoneRecords(ctx, conf.Client, domain, records); err != nil {
+			return diag.Errorf("Error creating DNS records: %s", err)
+		}
+	}
+
+	return readZoneResource(ctx, d, meta)
 }
 
 // readZoneResource is a function to read a DNS Zone.
@@ -66,6 +75,9 @@ func readZoneResource(ctx context.Context, d *schema.ResourceData, meta interfac
 	client := dns.New(conf.Client)
 	response, err := client.GetZone(ctx, dns.GetZoneRequest{DomainName: d.Id()})
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "domain")
+		}
 		return diag.Errorf("Error retrieving domain: %s", err)
 	}
 
@@ -73,11 +85,65 @@ func readZoneResource(ctx context.Context, d *schema.ResourceData, meta interfac
 		return diag.Errorf("Error retrieving domain: %s", response.Msg)
 	}
 
+	// the search comes back without the domain, rather than with an error, when the domain is gone.
+	if !hasZone(response, d.Id()) {
+		return helper.RemoveFromState(ctx, d, "domain")
+	}
+
+	if err := d.Set("name", d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// only read the records back if we are managing them,
+	// otherwise every zone would want to remove all of its records.
+	configured := expandZoneRecords(d.Get("record"))
+	if len(configured) == 0 {
+		return nil
+	}
+
+	records, err := listZoneRecords(ctx, client, d.Id())
+	if err != nil {
+		return diag.Errorf("Error retrieving DNS records: %s", err)
+	}
+
+	zoneRecords := make([]zoneRecord, 0, len(records))
+	for _, record := range records {
+		zoneRecords = append(zoneRecords, newZoneRecord(record, d.Id()))
+	}
+
+	if err := d.Set("record", flattenZoneRecords(withoutDefaultTTLs(zoneRecords, configured))); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
+// updateZoneResource is a function to update the records in a DNS Zone.
+func updateZoneResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogDNS)
+
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	// removing all the record blocks stops managing the records, rather than emptying the zone.
+	records := expandZoneRecords(d.Get("record"))
+	if d.HasChange("record") && len(records) > 0 {
+		if err := reconcileZoneRecords(ctx, conf.Client, d.Id(), records); err != nil {
+			return diag.Errorf("Error updating DNS records: %s", err)
+		}
+
+		tflog.SubsystemInfo(ctx, helper.LogDNS, "Updated DNS zone records", map[string]any{"domain": d.Id()})
+	}
+
+	return readZoneResource(ctx, d, meta)
+}
+
 // deleteZoneResource is a function to delete a DNS Zone.
 func deleteZoneResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogDNS)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -93,6 +159,8 @@ func deleteZoneResource(ctx context.Context, d *schema.ResourceData, meta interf
 		return diag.Errorf("Error deleting server: %s", resp.Msg)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogDNS, "Deleted DNS zone", map[string]any{"domain": d.Id()})
+
 	return nil
 }
 
@@ -103,6 +171,7 @@ func RecordResource() *schema.Resource {
 		ReadContext:   readRecordResource,
 		UpdateContext: updateRecordResource,
 		DeleteContext: deleteRecordResource,
+		CustomizeDiff: customizeRecordDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: importRecordResource,
 		},
@@ -112,31 +181,31 @@ func RecordResource() *schema.Resource {
 
 // createRecordResource is a function to create a new DNS Record.
 func createRecordResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogDNS)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
 	}
 
 	domain := fmt.Sprintf("%v", d.Get("domain"))
-	name := net.ConstructFqdn(fmt.Sprintf("%v", d.Get("name")), domain)
+	name := recordFqdn(fmt.Sprintf("%v", d.Get("name")), domain)
+	content, priority := recordContent(d)
 
-	domainRecord := models.DNSRecord{
-		Name:     strings.TrimSuffix(name, "."),
+	request := recordRequest{
 		Domain:   domain,
-		Content:  fmt.Sprintf("%v", d.Get("record")),
 		Type:     fmt.Sprintf("%v", d.Get("type")),
-		TTL:      fmt.Sprintf("%v", d.Get("ttl")),
-		Priority: fmt.Sprintf("%v", d.Get("priority")),
+		Name:     strings.TrimSuffix(name, "."),
+		Content:  content,
+		Priority: strconv.Itoa(priority),
 	}
 
-	client := dns.New(conf.Client)
-	resp, err := client.AddRecord(ctx, dns.AddRecordRequest{
-		Domain:   domainRecord.Domain,
-		Type:     domainRecord.Type,
-		Name:     domainRecord.Name,
-		Content:  domainRecord.Content,
-		Priority: domainRecord.Priority,
-	})
+	// the ttl is computed, leaving it out lets SiteHost pick the default.
+	if ttl, ok := d.GetOk("ttl"); ok {
+		request.TTL = fmt.Sprintf("%v", ttl)
+	}
+
+	resp, err := addRecord(ctx, conf.Client, request)
 	if err != nil {
 		return diag.Errorf("Error creating DNS record: %s", err)
 	}
@@ -145,9 +214,10 @@ func createRecordResource(ctx context.Context, d *schema.ResourceData, meta inte
 		return diag.Errorf("Error creating DNS record: %s", resp.Msg)
 	}
 
+	client := dns.New(conf.Client)
 	record, err := client.GetRecord(ctx, dns.RecordRequest{
 		ID:         resp.Return.ID,
-		DomainName: domainRecord.Domain,
+		DomainName: domain,
 	})
 	if err != nil {
 		return diag.Errorf("Error creating DNS record: %s", err)
@@ -157,7 +227,7 @@ func createRecordResource(ctx context.Context, d *schema.ResourceData, meta inte
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Domain Record: %s", d.Id())
+	tflog.SubsystemInfo(ctx, helper.LogDNS, "Created DNS record", map[string]any{"domain": request.Domain, "record_id": d.Id()})
 
 	return nil
 }
@@ -177,9 +247,17 @@ func readRecordResource(ctx context.Context, d *schema.ResourceData, meta interf
 		DomainName: domain,
 	})
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "DNS record")
+		}
 		return diag.Errorf("Error retrieving DNS record: %s", err)
 	}
 
+	// GoSH hands back an empty record, rather than an error, when the record is gone.
+	if record.ID == "" {
+		return helper.RemoveFromState(ctx, d, "DNS record")
+	}
+
 	if err := setRecordAttributes(d, record); err != nil {
 		return diag.FromErr(err)
 	}
@@ -189,6 +267,8 @@ func readRecordResource(ctx context.Context, d *schema.ResourceData, meta interf
 
 // deleteRecordResource is a function to delete a DNS Record.
 func deleteRecordResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogDNS)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -207,28 +287,38 @@ func deleteRecordResource(ctx context.Context, d *schema.ResourceData, meta inte
 		return diag.Errorf("Error deleting DNS record: %s", resp.Msg)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogDNS, "Deleted DNS record", map[string]any{"domain": d.Get("domain"), "record_id": d.Id()})
+
 	return nil
 }
 
 // updateRecordResource is a function to update a DNS Record.
 func updateRecordResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogDNS)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
 	}
 
-	client := dns.New(conf.Client)
-	resp, err := client.UpdateRecord(
-		ctx,
-		dns.UpdateRecordRequest{
-			Domain:   fmt.Sprintf("%v", d.Get("domain")),
-			RecordID: d.Id(),
-			Type:     fmt.Sprintf("%v", d.Get("type")),
-			Name:     fmt.Sprintf("%v", d.Get("name")),
-			Content:  fmt.Sprintf("%v", d.Get("content")),
-			Priority: fmt.Sprintf("%v", d.Get("priority")),
-		},
-	)
+	domain := fmt.Sprintf("%v", d.Get("domain"))
+	name := recordFqdn(fmt.Sprintf("%v", d.Get("name")), domain)
+	content, priority := recordContent(d)
+
+	request := recordRequest{
+		Domain:   domain,
+		RecordID: d.Id(),
+		Type:     fmt.Sprintf("%v", d.Get("type")),
+		Name:     strings.TrimSuffix(name, "."),
+		Content:  content,
+		Priority: strconv.Itoa(priority),
+	}
+
+	if ttl, ok := d.GetOk("ttl"); ok {
+		request.TTL = fmt.Sprintf("%v", ttl)
+	}
+
+	resp, err := updateRecord(ctx, conf.Client, request)
 	if err != nil {
 		return diag.Errorf("Error updating DNS record: %s", err)
 	}
@@ -237,12 +327,15 @@ func updateRecordResource(ctx context.Context, d *schema.ResourceData, meta inte
 		return diag.Errorf("Error updating DNS record: %s", resp.Msg)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogDNS, "Updated DNS record", map[string]any{"domain": domain, "record_id": d.Id()})
+
+	client := dns.New(conf.Client)
 	record, err := client.GetRecord(ctx, dns.RecordRequest{
 		ID:         d.Id(),
-		DomainName: fmt.Sprintf("%v", d.Get("domain")),
+		DomainName: domain,
 	})
 	if err != nil {
-		return diag.Errorf("Error creating DNS record: %s", err)
+		return diag.Errorf("Error updating DNS record: %s", err)
 	}
 
 	if err := setRecordAttributes(d, record); err != nil {
@@ -281,6 +374,12 @@ func setRecordAttributes(d *schema.ResourceData, record models.DNSRecord) error
 		return err
 	}
 
+	// the name comes back fully qualified without the trailing dot, as the name DiffSuppressFunc expects.
+	fqdn := net.ConstructFqdn(fmt.Sprintf("%v.", record.Name), record.Domain)
+	if err := d.Set("fqdn", strings.TrimSuffix(fqdn, ".")); err != nil {
+		return err
+	}
+
 	priority, err := strconv.Atoi(record.Priority)
 	if err != nil {
 		priority = 0
@@ -292,5 +391,23 @@ func setRecordAttributes(d *schema.ResourceData, record models.DNSRecord) error
 		return err
 	}
 
+	for recordType, key := range recordBlocks {
+		var block []map[string]interface{}
+		if strings.EqualFold(record.Type, recordType) {
+			block = flattenRecordBlock(record.Type, record.Content, priority)
+		}
+		if err := d.Set(key, block); err != nil {
+			return err
+		}
+	}
+
+	ttl, err := strconv.Atoi(record.TTL)
+	if err != nil {
+		ttl = 0
+	}
+	if err := d.Set("ttl", ttl); err != nil {
+		return err
+	}
+
 	return d.Set("change_date", record.ChangeDate)
 }
@@ -0,0 +1,251 @@
+package dns_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sitehostnz/gosh/pkg/api/dns"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+)
+
+func TestAccDNSZone(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckDNSZoneDestroy(t, api),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDNSZoneConfig(api, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_dns_zone.test", "id", "example.com"),
+					resource.TestCheckResourceAttr("sitehost_dns_zone.test", "name", "example.com"),
+					resource.TestCheckResourceAttr("sitehost_dns_zone.test", "record.#", "0"),
+				),
+			},
+			{
+				Config: testAccDNSZoneConfig(api, `
+  record {
+    name    = "www"
+    type    = "A"
+    content = "192.0.2.1"
+    ttl     = 300
+  }
+
+  record {
+    name     = "@"
+    type     = "MX"
+    content  = "mail.example.com"
+    priority = 10
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_dns_zone.test", "record.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("sitehost_dns_zone.test", "record.*", map[string]string{
+						"name":    "www",
+						"type":    "A",
+						"content": "192.0.2.1",
+						"ttl":     "300",
+					}),
+					// without a ttl, SiteHost picks it and it is left out of the state.
+					resource.TestCheckTypeSetElemNestedAttrs("sitehost_dns_zone.test", "record.*", map[string]string{
+						"name":     "@",
+						"type":     "MX",
+						"content":  "mail.example.com",
+						"priority": "10",
+						"ttl":      "0",
+					}),
+				),
+			},
+			{
+				ResourceName:      "sitehost_dns_zone.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the records are only managed when there are record blocks in the configuration, which an import doesn't have.
+				ImportStateVerifyIgnore: []string{"record"},
+			},
+		},
+	})
+}
+
+func TestAccDNSRecord(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckDNSRecordDestroy(t, api),
+			testAccCheckDNSZoneDestroy(t, api),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDNSRecordConfig(api, "192.0.2.1", 3600),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("sitehost_dns_record.a", "id"),
+					resource.TestCheckResourceAttr("sitehost_dns_record.a", "record", "192.0.2.1"),
+					resource.TestCheckResourceAttr("sitehost_dns_record.a", "ttl", "3600"),
+					resource.TestCheckResourceAttr("sitehost_dns_record.a", "fqdn", "api.example.com"),
+					resource.TestCheckResourceAttr("sitehost_dns_record.mx", "mx.0.preference", "10"),
+					resource.TestCheckResourceAttr("sitehost_dns_record.mx", "mx.0.exchange", "mail.example.com"),
+					resource.TestCheckResourceAttr("sitehost_dns_record.mx", "priority", "10"),
+				),
+			},
+			{
+				Config: testAccDNSRecordConfig(api, "192.0.2.2", 300),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_dns_record.a", "record", "192.0.2.2"),
+					resource.TestCheckResourceAttr("sitehost_dns_record.a", "ttl", "300"),
+				),
+			},
+			{
+				ResourceName:      "sitehost_dns_record.a",
+				ImportState:       true,
+				ImportStateIdFunc: testAccDNSRecordImportID("sitehost_dns_record.a"),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "sitehost_dns_record.mx",
+				ImportState:       true,
+				ImportStateIdFunc: testAccDNSRecordImportID("sitehost_dns_record.mx"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccDNSZoneFile(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		// the zone file leaves the records when it is destroyed, they go with the zone.
+		CheckDestroy: testAccCheckDNSZoneDestroy(t, api),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDNSZoneFileConfig(api, "www IN A 192.0.2.1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_dns_zone_file.test", "id", "example.com"),
+					resource.TestCheckTypeSetElemNestedAttrs("sitehost_dns_zone_file.test", "record.*", map[string]string{
+						"name":    "www",
+						"type":    "A",
+						"content": "192.0.2.1",
+						"ttl":     "1800",
+					}),
+				),
+			},
+			{
+				Config: testAccDNSZoneFileConfig(api, "www IN A 192.0.2.2\nmail 600 IN A 192.0.2.3"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("sitehost_dns_zone_file.test", "record.*", map[string]string{
+						"name":    "www",
+						"type":    "A",
+						"content": "192.0.2.2",
+						"ttl":     "1800",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("sitehost_dns_zone_file.test", "record.*", map[string]string{
+						"name":    "mail",
+						"type":    "A",
+						"content": "192.0.2.3",
+						"ttl":     "600",
+					}),
+				),
+			},
+			{
+				ResourceName:      "sitehost_dns_zone_file.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the zone file is what the records were loaded from, the API only has the records.
+				ImportStateVerifyIgnore: []string{"zone_file"},
+			},
+		},
+	})
+}
+
+func testAccCheckDNSZoneDestroy(t *testing.T, api *mockapi.Server) resource.TestCheckFunc {
+	t.Helper()
+
+	return acctest.CheckDestroy(t, api, "sitehost_dns_zone", func(ctx context.Context, conf *helper.CombinedConfig, rs *terraform.ResourceState) (bool, error) {
+		// the search comes back empty when the domain is gone.
+		response, err := dns.New(conf.Client).GetZone(ctx, dns.GetZoneRequest{DomainName: rs.Primary.ID})
+		return len(response.Return) > 0, err
+	})
+}
+
+func testAccCheckDNSRecordDestroy(t *testing.T, api *mockapi.Server) resource.TestCheckFunc {
+	t.Helper()
+
+	return acctest.CheckDestroy(t, api, "sitehost_dns_record", func(ctx context.Context, conf *helper.CombinedConfig, rs *terraform.ResourceState) (bool, error) {
+		// GoSH hands back an empty record when the record is gone.
+		record, err := dns.New(conf.Client).GetRecord(ctx, dns.RecordRequest{ID: rs.Primary.ID, DomainName: rs.Primary.Attributes["domain"]})
+		return record.ID != "", err
+	})
+}
+
+// testAccDNSRecordImportID returns the domain,record ID the record is imported with.
+func testAccDNSRecordImportID(name string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("%s not found in state", name)
+		}
+
+		return fmt.Sprintf("%s,%s", rs.Primary.Attributes["domain"], rs.Primary.ID), nil
+	}
+}
+
+func testAccDNSZoneConfig(api *mockapi.Server, records string) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_dns_zone" "test" {
+  name = "example.com"
+%s}
+`, records)
+}
+
+func testAccDNSRecordConfig(api *mockapi.Server, address string, ttl int) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_dns_zone" "test" {
+  name = "example.com"
+}
+
+resource "sitehost_dns_record" "a" {
+  domain = sitehost_dns_zone.test.name
+  name   = "api"
+  type   = "A"
+  record = %q
+  ttl    = %d
+}
+
+resource "sitehost_dns_record" "mx" {
+  domain = sitehost_dns_zone.test.name
+  name   = "@"
+  type   = "MX"
+
+  mx {
+    preference = 10
+    exchange   = "mail.example.com"
+  }
+}
+`, address, ttl)
+}
+
+func testAccDNSZoneFileConfig(api *mockapi.Server, records string) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_dns_zone" "test" {
+  name = "example.com"
+}
+
+resource "sitehost_dns_zone_file" "test" {
+  domain    = sitehost_dns_zone.test.name
+  zone_file = <<EOT
+$ORIGIN example.com.
+$TTL 1800
+%s
+EOT
+}
+`, records)
+}
@@ -0,0 +1,135 @@
+package dns
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// recordDataSourceResource is the schema for a DNS record in a data source.
+var recordDataSourceResource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The record ID",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The record name relative to the domain, `@` for the apex",
+		},
+		"fqdn": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The fully qualified record name",
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The record type",
+		},
+		"content": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The record content",
+		},
+		"priority": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The priority, for MX and SRV records",
+		},
+		"ttl": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The record TTL",
+		},
+	},
+}
+
+// zoneExportDataSourceSchema is the schema with values for a DNS zone export data source.
+var zoneExportDataSourceSchema = map[string]*schema.Schema{
+	"domain": {
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validation.NoZeroValues,
+		Description:  "The domain name",
+	},
+
+	"zone_file": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The zone rendered as a RFC 1035 master file",
+	},
+
+	"records": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The records in the zone",
+		Elem:        recordDataSourceResource,
+	},
+}
+
+// zonesDataSourceSchema is the schema with values for the DNS zones data source.
+var zonesDataSourceSchema = map[string]*schema.Schema{
+	"zones": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The DNS zones on the account",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The domain name",
+				},
+			},
+		},
+	},
+}
+
+// zoneDataSourceSchema is the schema with values for a DNS zone data source.
+var zoneDataSourceSchema = map[string]*schema.Schema{
+	"name": {
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validation.NoZeroValues,
+		Description:  "The domain name",
+	},
+
+	"records": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The records in the zone",
+		Elem:        recordDataSourceResource,
+	},
+}
+
+// recordsDataSourceSchema is the schema with values for the DNS records data source.
+var recordsDataSourceSchema = map[string]*schema.Schema{
+	"domain": {
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validation.NoZeroValues,
+		Description:  "The domain name",
+	},
+
+	"name": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Only return records with this name, relative to the domain or fully qualified with a trailing dot, `@` for the apex",
+	},
+
+	"type": {
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice(append([]string{"SOA"}, recordTypes...), true),
+		Description:  "Only return records of this type",
+	},
+
+	"records": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The matching records",
+		Elem:        recordDataSourceResource,
+	},
+}
@@ -9,6 +9,25 @@ import (
 	"github.com/sitehostnz/gosh/pkg/net"
 )
 
+// recordTypes are the DNS record types that can be managed.
+var recordTypes = []string{
+	"A",
+	"AAAA",
+	"CAA",
+	"CNAME",
+	"MX",
+	"TXT",
+	"SRV",
+	"NS", // added this back, as creating a zone does not appear to set the DNS records
+}
+
+// caaTags are the CAA property tags that can be set.
+var caaTags = []string{
+	"issue",
+	"issuewild",
+	"iodef",
+}
+
 // resourceZoneSchema is the schema with values for a DNS zone resource.
 var resourceZoneSchema = map[string]*schema.Schema{
 	"name": {
@@ -18,6 +37,58 @@ var resourceZoneSchema = map[string]*schema.Schema{
 		ValidateFunc: validation.NoZeroValues,
 		Description:  "The domain name",
 	},
+
+	// when records are set, the zone is managed authoritatively,
+	// records that are not in the configuration are removed.
+	"record": {
+		Type:     schema.TypeSet,
+		Optional: true,
+		Set:      hashZoneRecord,
+		Description: "The records in the zone. When set, the zone is managed authoritatively, " +
+			"records in SiteHost that are not in the configuration are removed. " +
+			"Removing all of the record blocks stops managing the records, rather than removing them",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.NoZeroValues,
+					Description:  "The record name relative to the domain, `@` for the apex",
+					StateFunc: func(v interface{}) string {
+						return strings.ToLower(fmt.Sprint(v))
+					},
+				},
+				"type": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringInSlice(recordTypes, false),
+					Description:  "The record type",
+				},
+				"content": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.NoZeroValues,
+					Description:  "The record content",
+					StateFunc: func(v interface{}) string {
+						return strings.TrimSuffix(fmt.Sprint(v), ".")
+					},
+				},
+				"priority": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntBetween(0, 65535),
+					Description:  "The priority, for MX and SRV records",
+				},
+				"ttl": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "The record TTL in seconds, SiteHost picks the default when it is not set",
+				},
+			},
+		},
+	},
 }
 
 // resourceRecordSchema is the schema with values for a DNS record resource.
@@ -47,28 +118,21 @@ var resourceRecordSchema = map[string]*schema.Schema{
 	},
 
 	"type": {
-		Type:     schema.TypeString,
-		Required: true,
-		ForceNew: true,
-		ValidateFunc: validation.StringInSlice([]string{
-			"A",
-			"AAAA",
-			"CAA",
-			"CNAME",
-			"MX",
-			"TXT",
-			"SRV",
-			"NS", // added this back, as creating a zone does not appear to set the DNS records
-		}, false),
-		Description: "The record type",
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.StringInSlice(recordTypes, false),
+		Description:  "The record type",
 	},
 
+	// computed, as the mx block sets the priority from its preference.
 	"priority": {
-		Type:         schema.TypeInt,
-		Optional:     true,
-		ValidateFunc: validation.IntBetween(0, 65535),
-		Description:  "The priority type",
-		Default:      0,
+		Type:          schema.TypeInt,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"mx"},
+		ValidateFunc:  validation.IntBetween(0, 65535),
+		Description:   "The priority, for MX and SRV records",
 	},
 
 	"ttl": {
@@ -76,11 +140,16 @@ var resourceRecordSchema = map[string]*schema.Schema{
 		Optional:     true,
 		Computed:     true,
 		ValidateFunc: validation.IntAtLeast(1),
+		Description:  "The record TTL in seconds, SiteHost picks the default when it is not set",
 	},
 
+	// computed, as the srv, caa and mx blocks are serialised into the record content.
 	"record": {
-		Type:     schema.TypeString,
-		Optional: true,
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"srv", "caa", "mx"},
+		Description:   "The record content, use the `srv`, `caa` or `mx` blocks for those record types instead",
 		DiffSuppressFunc: func(_, oldRecord, newRecord string, _ *schema.ResourceData) bool {
 			// bloody dots at the end of records...
 			// we have to do this, mainly for NS and CNAME records
@@ -89,9 +158,104 @@ var resourceRecordSchema = map[string]*schema.Schema{
 		},
 	},
 
+	"srv": {
+		Type:          schema.TypeList,
+		Optional:      true,
+		Computed:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"record", "caa", "mx"},
+		Description:   "The content of a SRV record, the priority is set with `priority`",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"weight": {
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validation.IntBetween(0, 65535),
+					Description:  "The weight of the target",
+				},
+				"port": {
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validation.IntBetween(0, 65535),
+					Description:  "The port the service is on",
+				},
+				"target": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.NoZeroValues,
+					Description:  "The host the service is on",
+					StateFunc: func(v interface{}) string {
+						return strings.TrimSuffix(fmt.Sprint(v), ".")
+					},
+				},
+			},
+		},
+	},
+
+	"caa": {
+		Type:          schema.TypeList,
+		Optional:      true,
+		Computed:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"record", "srv", "mx"},
+		Description:   "The content of a CAA record",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"flags": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntBetween(0, 255),
+					Description:  "The flags, 128 marks the property as critical",
+				},
+				"tag": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringInSlice(caaTags, false),
+					Description:  "The property tag",
+				},
+				"value": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.NoZeroValues,
+					Description:  "The property value, e.g. the certificate authority",
+				},
+			},
+		},
+	},
+
+	"mx": {
+		Type:          schema.TypeList,
+		Optional:      true,
+		Computed:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"record", "srv", "caa"},
+		Description:   "The content of a MX record, the preference sets `priority`",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"preference": {
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validation.IntBetween(0, 65535),
+					Description:  "The preference, lower is preferred",
+				},
+				"exchange": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.NoZeroValues,
+					Description:  "The mail server",
+					StateFunc: func(v interface{}) string {
+						return strings.TrimSuffix(fmt.Sprint(v), ".")
+					},
+				},
+			},
+		},
+	},
+
 	"fqdn": {
-		Type:     schema.TypeString,
-		Computed: true,
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The fully qualified name of the record, without the trailing dot",
 	},
 
 	"change_date": {
@@ -99,3 +263,58 @@ var resourceRecordSchema = map[string]*schema.Schema{
 		Computed: true,
 	},
 }
+
+// resourceZoneFileSchema is the schema with values for a DNS zone file resource.
+var resourceZoneFileSchema = map[string]*schema.Schema{
+	"domain": {
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.NoZeroValues,
+		Description:  "The domain name, the zone must already exist",
+	},
+
+	"zone_file": {
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validation.NoZeroValues,
+		Description: "The zone in RFC 1035 master file format. The records in the zone are reconciled against it, " +
+			"records in SiteHost that are not in the zone file are removed. The SOA record is ignored",
+	},
+
+	"record": {
+		Type:        schema.TypeSet,
+		Computed:    true,
+		Set:         hashZoneRecord,
+		Description: "The records currently in the zone",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The record name relative to the domain, `@` for the apex",
+				},
+				"type": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The record type",
+				},
+				"content": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The record content",
+				},
+				"priority": {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: "The priority, for MX and SRV records",
+				},
+				"ttl": {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: "The record TTL in seconds",
+				},
+			},
+		},
+	},
+}
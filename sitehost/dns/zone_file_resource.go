@@ -0,0 +1,134 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sitehostnz/gosh/pkg/api/dns"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+)
+
+// ZoneFileResource returns a schema with the operations for DNS Zone File resource.
+func ZoneFileResource() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createZoneFileResource,
+		ReadContext:   readZoneFileResource,
+		UpdateContext: updateZoneFileResource,
+		DeleteContext: deleteZoneFileResource,
+		CustomizeDiff: customizeZoneFileDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: resourceZoneFileSchema,
+	}
+}
+
+// createZoneFileResource is a function to load a zone file into a DNS Zone.
+func createZoneFileResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogDNS)
+
+	domain := fmt.Sprintf("%v", d.Get("domain"))
+
+	if err := applyZoneFile(ctx, d, meta, domain); err != nil {
+		return diag.Errorf("Error loading zone file: %s", err)
+	}
+
+	d.SetId(domain)
+
+	tflog.SubsystemInfo(ctx, helper.LogDNS, "Applied zone file", map[string]any{"domain": d.Id()})
+
+	return readZoneFileResource(ctx, d, meta)
+}
+
+// readZoneFileResource is a function to read the records of a DNS Zone loaded from a zone file.
+func readZoneFileResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	client := dns.New(conf.Client)
+	records, err := listZoneRecords(ctx, client, d.Id())
+	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "domain")
+		}
+		return diag.Errorf("Error retrieving DNS records: %s", err)
+	}
+
+	zoneRecords := make([]zoneRecord, 0, len(records))
+	for _, record := range records {
+		zoneRecords = append(zoneRecords, newZoneRecord(record, d.Id()))
+	}
+
+	if err := d.Set("domain", d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("record", flattenZoneRecords(zoneRecords)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// updateZoneFileResource is a function to reconcile a DNS Zone against a changed zone file.
+func updateZoneFileResource(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogDNS)
+
+	if err := applyZoneFile(ctx, d, meta, d.Id()); err != nil {
+		return diag.Errorf("Error loading zone file: %s", err)
+	}
+
+	tflog.SubsystemInfo(ctx, helper.LogDNS, "Applied zone file", map[string]any{"domain": d.Id()})
+
+	return readZoneFileResource(ctx, d, meta)
+}
+
+// deleteZoneFileResource is a function to stop managing a DNS Zone from a zone file.
+// The records are left in place, deleting the zone itself is up to the sitehost_dns_zone resource.
+func deleteZoneFileResource(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+// applyZoneFile parses the zone file and reconciles the records in the zone against it.
+func applyZoneFile(ctx context.Context, d *schema.ResourceData, meta interface{}, domain string) error {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return fmt.Errorf("failed to convert meta object")
+	}
+
+	records, err := parseZoneFile(fmt.Sprintf("%v", d.Get("zone_file")), domain)
+	if err != nil {
+		return err
+	}
+
+	return reconcileZoneRecords(ctx, conf.Client, domain, records)
+}
+
+// customizeZoneFileDiff checks the zone file parses, and plans an update when the records in the zone have drifted from it.
+func customizeZoneFileDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	domain := fmt.Sprintf("%v", d.Get("domain"))
+	zoneFile := fmt.Sprintf("%v", d.Get("zone_file"))
+	if !d.NewValueKnown("domain") || !d.NewValueKnown("zone_file") || domain == "" || zoneFile == "" {
+		return nil
+	}
+
+	records, err := parseZoneFile(zoneFile, domain)
+	if err != nil {
+		return fmt.Errorf("invalid zone file: %w", err)
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	if !matchesRecords(records, expandZoneRecords(d.Get("record"))) {
+		return d.SetNewComputed("record")
+	}
+
+	return nil
+}
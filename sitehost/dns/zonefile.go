@@ -0,0 +1,465 @@
+package dns
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/gosh/pkg/net"
+)
+
+type (
+	// zoneFileToken is a single token from a zone file, quoted tokens are kept apart so spaces and `;` inside them survive.
+	zoneFileToken struct {
+		value  string
+		quoted bool
+	}
+
+	// zoneFileLine is a logical line from a zone file, parentheses can spread one over many physical lines.
+	zoneFileLine struct {
+		tokens     []zoneFileToken
+		blankOwner bool
+		number     int
+	}
+)
+
+// tokeniseZoneFile splits a RFC 1035 master file into logical lines of tokens, dropping comments.
+func tokeniseZoneFile(content string) ([]zoneFileLine, error) {
+	var (
+		lines      []zoneFileLine
+		current    zoneFileLine
+		token      strings.Builder
+		inToken    bool
+		inQuote    bool
+		parens     int
+		lineNumber = 1
+		lineStart  = true
+	)
+
+	endToken := func(quoted bool) {
+		if inToken || quoted {
+			current.tokens = append(current.tokens, zoneFileToken{value: token.String(), quoted: quoted})
+		}
+		token.Reset()
+		inToken = false
+	}
+
+	endLine := func() {
+		if len(current.tokens) > 0 {
+			lines = append(lines, current)
+		}
+		current = zoneFileLine{}
+		lineStart = true
+	}
+
+	runes := []rune(content)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+
+		if lineStart && parens == 0 {
+			current = zoneFileLine{number: lineNumber, blankOwner: c == ' ' || c == '\t'}
+			lineStart = false
+		}
+
+		switch {
+		case inQuote && c == '"':
+			endToken(true)
+			inQuote = false
+		case c == '\\' && i+1 < len(runes):
+			// \DDD is a decimal byte, anything else is the character itself.
+			if i+3 < len(runes) && isDigits(string(runes[i+1:i+4])) {
+				b, _ := strconv.Atoi(string(runes[i+1 : i+4]))
+				token.WriteByte(byte(b))
+				i += 3
+			} else {
+				i++
+				token.WriteRune(runes[i])
+			}
+			inToken = true
+		case inQuote:
+			if c == '\n' {
+				lineNumber++
+			}
+			token.WriteRune(c)
+		case c == '"':
+			endToken(false)
+			inQuote = true
+		case c == ';':
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+		case c == '(':
+			endToken(false)
+			parens++
+		case c == ')':
+			endToken(false)
+			parens--
+			if parens < 0 {
+				return nil, fmt.Errorf("line %d: unbalanced parentheses", lineNumber)
+			}
+		case c == '\n':
+			endToken(false)
+			if parens == 0 {
+				endLine()
+			}
+			lineNumber++
+		case c == ' ' || c == '\t' || c == '\r':
+			endToken(false)
+		default:
+			token.WriteRune(c)
+			inToken = true
+		}
+	}
+
+	if inQuote {
+		return nil, fmt.Errorf("line %d: unterminated quoted string", lineNumber)
+	}
+
+	if parens != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", lineNumber)
+	}
+
+	endToken(false)
+	endLine()
+
+	return lines, nil
+}
+
+// isDigits checks if a string is made up of only decimal digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseTTL parses a TTL, either in seconds or using the BIND units e.g. `1h30m`.
+func parseTTL(s string) (int, error) {
+	if isDigits(s) {
+		return strconv.Atoi(s)
+	}
+
+	units := map[rune]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+
+	total, number := 0, ""
+	for _, c := range strings.ToLower(s) {
+		switch {
+		case c >= '0' && c <= '9':
+			number += string(c)
+		case units[c] > 0 && number != "":
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return 0, err
+			}
+			total += n * units[c]
+			number = ""
+		default:
+			return 0, fmt.Errorf("invalid ttl %s", s)
+		}
+	}
+
+	if number != "" {
+		return 0, fmt.Errorf("invalid ttl %s", s)
+	}
+
+	return total, nil
+}
+
+// isClass checks if the token is a DNS class.
+func isClass(s string) bool {
+	switch strings.ToUpper(s) {
+	case "IN", "CH", "HS", "CS":
+		return true
+	default:
+		return false
+	}
+}
+
+// absoluteName resolves a name in a zone file against the origin, the result always ends with a dot.
+func absoluteName(name, origin string) string {
+	name = strings.ToLower(name)
+
+	switch {
+	case name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return name
+	default:
+		return name + "." + origin
+	}
+}
+
+// parseZoneFile parses a RFC 1035 master file into the records for the domain.
+// $ORIGIN, $TTL, relative names, omitted owners and multi-string TXT records are supported,
+// the SOA is skipped as it belongs to SiteHost. Records without a TTL get the $TTL, or 0 to leave it to SiteHost.
+func parseZoneFile(content, domain string) ([]zoneRecord, error) {
+	lines, err := tokeniseZoneFile(content)
+	if err != nil {
+		return nil, err
+	}
+
+	apex := net.ConstructFqdn("@", domain)
+	origin := apex
+	owner := ""
+	defaultTTL := 0
+	records := make([]zoneRecord, 0, len(lines))
+
+	for _, line := range lines {
+		tokens := line.tokens
+
+		if first := tokens[0]; !first.quoted && strings.HasPrefix(first.value, "$") {
+			switch strings.ToUpper(first.value) {
+			case "$ORIGIN":
+				if len(tokens) != 2 {
+					return nil, fmt.Errorf("line %d: $ORIGIN takes a single domain name", line.number)
+				}
+				origin = absoluteName(tokens[1].value, origin)
+			case "$TTL":
+				if len(tokens) != 2 {
+					return nil, fmt.Errorf("line %d: $TTL takes a single ttl", line.number)
+				}
+				if defaultTTL, err = parseTTL(tokens[1].value); err != nil {
+					return nil, fmt.Errorf("line %d: %w", line.number, err)
+				}
+			default:
+				return nil, fmt.Errorf("line %d: unsupported directive %s", line.number, first.value)
+			}
+			continue
+		}
+
+		if !line.blankOwner {
+			owner = absoluteName(tokens[0].value, origin)
+			tokens = tokens[1:]
+		}
+
+		if owner == "" {
+			return nil, fmt.Errorf("line %d: the first record must have a name", line.number)
+		}
+
+		if owner != apex && !strings.HasSuffix(owner, "."+apex) {
+			return nil, fmt.Errorf("line %d: %s is not in the zone %s", line.number, owner, apex)
+		}
+
+		// the ttl and class are both optional and can come in either order.
+		ttl := defaultTTL
+		for j := 0; j < 2 && len(tokens) > 0; j++ {
+			if isClass(tokens[0].value) {
+				if !strings.EqualFold(tokens[0].value, "IN") {
+					return nil, fmt.Errorf("line %d: only the IN class is supported", line.number)
+				}
+				tokens = tokens[1:]
+			} else if v := tokens[0].value; !tokens[0].quoted && v != "" && isDigits(v[:1]) {
+				if ttl, err = parseTTL(v); err != nil {
+					return nil, fmt.Errorf("line %d: %w", line.number, err)
+				}
+				tokens = tokens[1:]
+			}
+		}
+
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("line %d: missing record type", line.number)
+		}
+
+		recordType := strings.ToUpper(tokens[0].value)
+		if recordType == "SOA" {
+			continue
+		}
+
+		record, err := parseRecordData(recordType, tokens[1:], origin)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.number, err)
+		}
+
+		record.Name = net.DeconstructFqdn(owner, domain)
+		record.TTL = ttl
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// parseRecordData parses the data of a record into the SiteHost content and priority.
+func parseRecordData(recordType string, tokens []zoneFileToken, origin string) (zoneRecord, error) {
+	record := zoneRecord{Type: recordType}
+
+	values := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		values = append(values, t.value)
+	}
+
+	expect := func(n int) error {
+		if len(values) != n {
+			return fmt.Errorf("%s records take %d values, got %d", recordType, n, len(values))
+		}
+		return nil
+	}
+
+	switch recordType {
+	case "A", "AAAA":
+		if err := expect(1); err != nil {
+			return record, err
+		}
+
+		addr, err := netip.ParseAddr(values[0])
+		if err != nil || addr.Is4() != (recordType == "A") {
+			return record, fmt.Errorf("invalid address %s for a %s record", values[0], recordType)
+		}
+		record.Content = addr.String()
+	case "CNAME", "NS":
+		if err := expect(1); err != nil {
+			return record, err
+		}
+		record.Content = strings.TrimSuffix(absoluteName(values[0], origin), ".")
+	case "MX":
+		if err := expect(2); err != nil {
+			return record, err
+		}
+
+		preference, err := strconv.Atoi(values[0])
+		if err != nil {
+			return record, fmt.Errorf("invalid MX preference %s", values[0])
+		}
+		record.Priority = preference
+		record.Content = strings.TrimSuffix(absoluteName(values[1], origin), ".")
+	case "SRV":
+		if err := expect(4); err != nil {
+			return record, err
+		}
+
+		for _, v := range values[:3] {
+			if !isDigits(v) {
+				return record, fmt.Errorf("invalid SRV value %s", v)
+			}
+		}
+
+		record.Priority, _ = strconv.Atoi(values[0])
+		record.Content = fmt.Sprintf("%s %s %s", values[1], values[2], strings.TrimSuffix(absoluteName(values[3], origin), "."))
+	case "CAA":
+		if err := expect(3); err != nil {
+			return record, err
+		}
+
+		if !isDigits(values[0]) {
+			return record, fmt.Errorf("invalid CAA flags %s", values[0])
+		}
+		record.Content = fmt.Sprintf("%s %s \"%s\"", values[0], strings.ToLower(values[1]), values[2])
+	case "TXT":
+		if len(values) == 0 {
+			return record, fmt.Errorf("TXT records need at least one string")
+		}
+
+		// long TXT records, DKIM keys and the like, are split into strings that are joined back together.
+		record.Content = strings.Join(values, "")
+	default:
+		return record, fmt.Errorf("unsupported record type %s", recordType)
+	}
+
+	return record, nil
+}
+
+// txtChunkSize is the longest a single character string in a TXT record can be.
+const txtChunkSize = 255
+
+// renderZoneFile renders the records of a domain as a canonical RFC 1035 master file.
+// The SOA comes first, then the records sorted by name, type, priority and content, each with an explicit TTL.
+func renderZoneFile(domain string, records []models.DNSRecord) string {
+	apex := net.ConstructFqdn("@", domain)
+
+	sorted := make([]models.DNSRecord, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := newZoneRecord(sorted[i], domain), newZoneRecord(sorted[j], domain)
+
+		if soaA, soaB := a.Type == "SOA", b.Type == "SOA"; soaA != soaB {
+			return soaA
+		}
+		if a.Name != b.Name {
+			return a.Name == "@" || (b.Name != "@" && a.Name < b.Name)
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.Content < b.Content
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s\n", apex)
+
+	for _, record := range sorted {
+		r := newZoneRecord(record, domain)
+
+		fields := []string{r.Name}
+		if record.TTL != "" {
+			fields = append(fields, record.TTL)
+		}
+		fields = append(fields, "IN", r.Type, renderRecordData(record, r))
+
+		b.WriteString(strings.Join(fields, "\t"))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderRecordData renders the data of a record in master file syntax, names in the data are written fully qualified.
+func renderRecordData(record models.DNSRecord, r zoneRecord) string {
+	switch r.Type {
+	case "CNAME", "NS":
+		return r.Content + "."
+	case "MX", "SRV":
+		return fmt.Sprintf("%d %s.", r.Priority, r.Content)
+	case "SOA":
+		// the nameserver and mailbox come back without the trailing dots.
+		fields := strings.Fields(record.Content)
+		for i := 0; i < len(fields) && i < 2; i++ {
+			if !strings.HasSuffix(fields[i], ".") {
+				fields[i] += "."
+			}
+		}
+		return strings.Join(fields, " ")
+	case "TXT":
+		return quoteTXT(r.Content)
+	default:
+		return r.Content
+	}
+}
+
+// quoteTXT quotes the content of a TXT record, splitting it into strings no longer than a TXT string can be.
+func quoteTXT(content string) string {
+	chunks := make([]string, 0, len(content)/txtChunkSize+1)
+	for len(content) > txtChunkSize {
+		// split on a rune boundary, so a multi-byte character isn't cut in half.
+		end := txtChunkSize
+		for end > 0 && !utf8.RuneStart(content[end]) {
+			end--
+		}
+		if end == 0 {
+			end = txtChunkSize
+		}
+
+		chunks = append(chunks, content[:end])
+		content = content[end:]
+	}
+	chunks = append(chunks, content)
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	for i, chunk := range chunks {
+		chunks[i] = `"` + replacer.Replace(chunk) + `"`
+	}
+
+	return strings.Join(chunks, " ")
+}
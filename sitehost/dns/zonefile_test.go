@@ -0,0 +1,239 @@
+package dns
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sitehostnz/gosh/pkg/models"
+)
+
+func TestParseZoneFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []zoneRecord
+		err     string
+	}{
+		{
+			name: "origin and ttl",
+			content: `$ORIGIN example.com.
+$TTL 1h
+www	IN	A	192.0.2.1
+mail	300	IN	A	192.0.2.2
+`,
+			want: []zoneRecord{
+				{Name: "www", Type: "A", Content: "192.0.2.1", TTL: 3600},
+				{Name: "mail", Type: "A", Content: "192.0.2.2", TTL: 300},
+			},
+		},
+		{
+			name: "relative and apex names",
+			content: `@	IN	MX	10 mail
+$ORIGIN sub.example.com.
+www	IN	CNAME	@
+ftp	IN	CNAME	www.example.com.
+	IN	AAAA	2001:db8::1
+`,
+			want: []zoneRecord{
+				{Name: "@", Type: "MX", Content: "mail.example.com", Priority: 10},
+				{Name: "www.sub", Type: "CNAME", Content: "sub.example.com"},
+				{Name: "ftp.sub", Type: "CNAME", Content: "www.example.com"},
+				{Name: "ftp.sub", Type: "AAAA", Content: "2001:db8::1"},
+			},
+		},
+		{
+			name:    "multi-string TXT",
+			content: `@	IN	TXT	"v=DKIM1; k=rsa; " "p=MIGf" "MA0G"` + "\n",
+			want: []zoneRecord{
+				{Name: "@", Type: "TXT", Content: "v=DKIM1; k=rsa; p=MIGfMA0G"},
+			},
+		},
+		{
+			name: "comments and parentheses",
+			content: `; the mail server
+@	IN	SOA	ns1.sitehost.co.nz. hostmaster.sitehost.co.nz. (
+		1 ; serial
+		10800 3600 604800 3600 )
+_sip._tcp	IN	SRV	(10 20 ; priority and weight
+		5060 sip ) ; port and target
+@	IN	CAA	0 issue "letsencrypt.org; validationmethods=dns-01"
+`,
+			want: []zoneRecord{
+				{Name: "_sip._tcp", Type: "SRV", Content: "20 5060 sip.example.com", Priority: 10},
+				{Name: "@", Type: "CAA", Content: `0 issue "letsencrypt.org; validationmethods=dns-01"`},
+			},
+		},
+		{
+			name:    "escaped characters",
+			content: `@	IN	TXT	"say \"hi\"\059 caf\195\169"` + "\n",
+			want: []zoneRecord{
+				{Name: "@", Type: "TXT", Content: `say "hi"; café`},
+			},
+		},
+		{
+			name:    "outside the zone",
+			content: "www.example.org.	IN	A	192.0.2.1\n",
+			err:     "line 1: www.example.org. is not in the zone example.com.",
+		},
+		{
+			name:    "unbalanced parentheses",
+			content: "@	IN	TXT	( \"a\"\n",
+			err:     "unbalanced parentheses",
+		},
+		{
+			name:    "unterminated string",
+			content: "@	IN	TXT	\"a\n",
+			err:     "unterminated quoted string",
+		},
+		{
+			name:    "missing owner",
+			content: "	IN	A	192.0.2.1\n",
+			err:     "line 1: the first record must have a name",
+		},
+		{
+			name:    "wrong address family",
+			content: "www	IN	A	2001:db8::1\n",
+			err:     "invalid address 2001:db8::1 for a A record",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseZoneFile(tt.content, "example.com")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("parseZoneFile() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseZoneFile() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseZoneFile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	tests := map[string]int{"300": 300, "1h30m": 5400, "1W": 604800, "2d": 172800}
+	for s, want := range tests {
+		if got, err := parseTTL(s); err != nil || got != want {
+			t.Errorf("parseTTL(%q) = %d, %v, want %d", s, got, err, want)
+		}
+	}
+
+	for _, s := range []string{"1x", "h", "10m5"} {
+		if _, err := parseTTL(s); err == nil {
+			t.Errorf("parseTTL(%q) did not fail", s)
+		}
+	}
+}
+
+func TestQuoteTXT(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "short",
+			content: `v=spf1 include:"x" \ -all`,
+			want:    []string{`"v=spf1 include:\"x\" \\ -all"`},
+		},
+		{
+			name:    "exactly one string",
+			content: strings.Repeat("a", 255),
+			want:    []string{`"` + strings.Repeat("a", 255) + `"`},
+		},
+		{
+			name:    "split",
+			content: strings.Repeat("a", 300),
+			want:    []string{`"` + strings.Repeat("a", 255) + `"`, `"` + strings.Repeat("a", 45) + `"`},
+		},
+		{
+			// é is two bytes and would straddle the 255th byte.
+			name:    "split on a rune boundary",
+			content: strings.Repeat("a", 254) + "é" + "b",
+			want:    []string{`"` + strings.Repeat("a", 254) + `"`, `"éb"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := quoteTXT(tt.content)
+			if want := strings.Join(tt.want, " "); got != want {
+				t.Errorf("quoteTXT() = %q, want %q", got, want)
+			}
+
+			for _, s := range strings.Split(got, `" "`) {
+				if !utf8.ValidString(s) {
+					t.Errorf("quoteTXT() split a character in %q", s)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderZoneFile(t *testing.T) {
+	records := []models.DNSRecord{
+		{Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "300"},
+		{Name: "example.com", Type: "MX", Content: "mail.example.com", Priority: "10", TTL: "3600"},
+		{Name: "example.com", Type: "SOA", Content: "ns1.sitehost.co.nz hostmaster.sitehost.co.nz 1 10800 3600 604800 3600", TTL: "3600"},
+		{Name: "example.com", Type: "TXT", Content: "v=spf1 -all", TTL: "3600"},
+		{Name: "_sip._tcp.example.com", Type: "SRV", Content: "20 5060 sip.example.com", Priority: "10", TTL: "3600"},
+		{Name: "example.com", Type: "CAA", Content: `0 issue "letsencrypt.org"`, TTL: "3600"},
+	}
+
+	want := `$ORIGIN example.com.
+@	3600	IN	SOA	ns1.sitehost.co.nz. hostmaster.sitehost.co.nz. 1 10800 3600 604800 3600
+@	3600	IN	CAA	0 issue "letsencrypt.org"
+@	3600	IN	MX	10 mail.example.com.
+@	3600	IN	TXT	"v=spf1 -all"
+_sip._tcp	3600	IN	SRV	10 20 5060 sip.example.com.
+www	300	IN	A	192.0.2.1
+`
+
+	if got := renderZoneFile("example.com", records); got != want {
+		t.Errorf("renderZoneFile() = %s, want %s", got, want)
+	}
+}
+
+func TestZoneFileRoundTrip(t *testing.T) {
+	records := []models.DNSRecord{
+		{Name: "example.com", Type: "SOA", Content: "ns1.sitehost.co.nz hostmaster.sitehost.co.nz 1 10800 3600 604800 3600", TTL: "3600"},
+		{Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "300"},
+		{Name: "example.com", Type: "NS", Content: "ns1.sitehost.co.nz", TTL: "3600"},
+		{Name: "www.example.com", Type: "CNAME", Content: "example.com", TTL: "300"},
+		{Name: "example.com", Type: "MX", Content: "mail.example.com", Priority: "10", TTL: "3600"},
+		{Name: "ipv6.example.com", Type: "AAAA", Content: "2001:db8::1", TTL: "3600"},
+		{Name: "_sip._tcp.example.com", Type: "SRV", Content: "20 5060 sip.example.com", Priority: "10", TTL: "3600"},
+		{Name: "example.com", Type: "CAA", Content: `0 issue "letsencrypt.org; validationmethods=dns-01"`, TTL: "3600"},
+		{Name: "dkim._domainkey.example.com", Type: "TXT", Content: `v=DKIM1; p=` + strings.Repeat("café", 100) + `"\`, TTL: "3600"},
+	}
+
+	got, err := parseZoneFile(renderZoneFile("example.com", records), "example.com")
+	if err != nil {
+		t.Fatalf("parseZoneFile() error = %v", err)
+	}
+
+	// the SOA belongs to SiteHost and is skipped.
+	want := make([]zoneRecord, 0, len(records))
+	for _, record := range records[1:] {
+		want = append(want, newZoneRecord(record, "example.com"))
+	}
+
+	key := func(r zoneRecord) string { return r.Name + " " + r.Type }
+	sort.Slice(got, func(i, j int) bool { return key(got[i]) < key(got[j]) })
+	sort.Slice(want, func(i, j int) bool { return key(want[i]) < key(want[j]) })
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseZoneFile(renderZoneFile()) = %+v, want %+v", got, want)
+	}
+}
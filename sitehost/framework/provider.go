@@ -0,0 +1,152 @@
+// Package framework provides the SiteHost provider on terraform-plugin-framework.
+// It is served alongside the SDKv2 provider through a mux server, new resources and data sources are written here.
+package framework
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+)
+
+// Provider is the SiteHost provider on terraform-plugin-framework.
+type Provider struct {
+	version string
+}
+
+// providerModel is the provider configuration.
+type providerModel struct {
+	ClientID    types.String `tfsdk:"client_id"`
+	APIKey      types.String `tfsdk:"api_key"`
+	APIEndpoint types.String `tfsdk:"api_endpoint"`
+
+	MaxRetries        types.Int64   `tfsdk:"max_retries"`
+	RetryMaxWait      types.String  `tfsdk:"retry_max_wait"`
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+}
+
+var _ provider.Provider = &Provider{}
+
+// New returns a provider.Provider for SiteHost.
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &Provider{version: version}
+	}
+}
+
+// Metadata returns the provider type name.
+func (p *Provider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "sitehost"
+	resp.Version = p.version
+}
+
+// Schema returns the provider configuration, it has to match the schema of the SDKv2 provider for the mux server.
+func (p *Provider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"client_id": schema.StringAttribute{
+				Optional:    true,
+				Description: helper.ClientIDDescription,
+			},
+			"api_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: helper.APIKeyDescription,
+			},
+			"api_endpoint": schema.StringAttribute{
+				Optional:    true,
+				Description: helper.APIEndpointDescription,
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: helper.MaxRetriesDescription,
+			},
+			"retry_max_wait": schema.StringAttribute{
+				Optional:    true,
+				Description: helper.RetryMaxWaitDescription,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Optional:    true,
+				Description: helper.RequestsPerSecondDescription,
+			},
+		},
+	}
+}
+
+// Configure creates the API client, with the same Config as the SDKv2 provider.
+func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var data providerModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the values may not be known yet, in that case there is nothing to configure until they are.
+	if data.ClientID.IsUnknown() || data.APIKey.IsUnknown() || data.APIEndpoint.IsUnknown() ||
+		data.MaxRetries.IsUnknown() || data.RetryMaxWait.IsUnknown() || data.RequestsPerSecond.IsUnknown() {
+		return
+	}
+
+	// the defaults are the ones the SDKv2 provider sets in its schema.
+	config := &helper.Config{
+		ClientID:          stringOrEnv(data.ClientID, "SH_CLIENT_ID"),
+		APIKey:            stringOrEnv(data.APIKey, "SH_APIKEY"),
+		APIEndpoint:       data.APIEndpoint.ValueString(),
+		TerraformVersion:  p.version,
+		MaxRetries:        helper.DefaultMaxRetries,
+		RetryMaxWait:      helper.DefaultRetryMaxWait,
+		RequestsPerSecond: helper.DefaultRequestsPerSecond,
+	}
+
+	if !data.MaxRetries.IsNull() {
+		config.MaxRetries = int(data.MaxRetries.ValueInt64())
+	}
+
+	if !data.RetryMaxWait.IsNull() {
+		retryMaxWait, err := time.ParseDuration(data.RetryMaxWait.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid provider configuration", "retry_max_wait must be a duration, such as 30s: "+err.Error())
+			return
+		}
+
+		config.RetryMaxWait = retryMaxWait
+	}
+
+	if !data.RequestsPerSecond.IsNull() {
+		config.RequestsPerSecond = data.RequestsPerSecond.ValueFloat64()
+	}
+
+	if err := config.Validate(); err != nil {
+		resp.Diagnostics.AddError("Invalid provider configuration", err.Error())
+		return
+	}
+
+	// there are no resources or data sources here yet, so no client is made, the SDKv2 provider's client is the only one,
+	// and all the requests share its rate limit. The first resource here will need to share that client, rather than make another.
+}
+
+// Resources returns the resources written on the framework.
+func (p *Provider) Resources(_ context.Context) []func() resource.Resource {
+	return []func() resource.Resource{}
+}
+
+// DataSources returns the data sources written on the framework.
+func (p *Provider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{}
+}
+
+// stringOrEnv returns the configured value, or the environment variable when it isn't set.
+func stringOrEnv(v types.String, env string) string {
+	if v.IsNull() {
+		return os.Getenv(env)
+	}
+
+	return v.ValueString()
+}
@@ -0,0 +1,72 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"unsafe"
+
+	"github.com/sitehostnz/gosh/pkg/api"
+	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/gosh/pkg/net"
+)
+
+// GoSH does not (yet) cover every endpoint, or every parameter of the ones it does cover, that the provider needs.
+// The requests it is missing are made with Post and Get, from an api.go next to the resources that need them,
+// and can go once GoSH covers them. The same goes for setHTTPClient, once GoSH takes an http client.
+
+// setHTTPClient makes the client send its requests with the http client, GoSH has no option for it and sends them with
+// a client of its own, with http.DefaultTransport. It fails, rather than leave the requests without retries, if the
+// client GoSH keeps changes.
+func setHTTPClient(client *api.Client, httpClient *http.Client) error {
+	field := reflect.ValueOf(client).Elem().FieldByName("client")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(httpClient) {
+		return errors.New("the GoSH client has changed, the SiteHost client can't send requests with its transport")
+	}
+
+	//nolint:gosec // the field is unexported, and the client is our own.
+	reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Set(reflect.ValueOf(httpClient))
+
+	return nil
+}
+
+// JobResponse is the response from the endpoints that queue a job.
+type JobResponse struct {
+	Return struct {
+		models.Job `json:"job"`
+	} `json:"return"`
+	models.APIResponse
+}
+
+// Post sends the values to the endpoint, in the order of the keys and with the client ID first, and decodes the response.
+func Post(ctx context.Context, client *api.Client, uri string, keys []string, values url.Values, response any) error {
+	values.Set("client_id", client.ClientID)
+
+	req, err := client.NewRequest("POST", uri, net.Encode(values, append([]string{"client_id"}, keys...)))
+	if err != nil {
+		return err
+	}
+
+	// GoSH doesn't pass the context on to the request, so it is set here, for cancelling and logging.
+	return client.Do(ctx, withRequestContext(ctx, req), response)
+}
+
+// Get gets the endpoint, with the options, if there are any, in the query, and decodes the response.
+func Get(ctx context.Context, client *api.Client, uri string, opt any, response any) error {
+	if opt != nil {
+		var err error
+		if uri, err = net.AddOptions(uri, opt); err != nil {
+			return err
+		}
+	}
+
+	req, err := client.NewRequest("GET", uri, "")
+	if err != nil {
+		return err
+	}
+
+	// GoSH doesn't pass the context on to the request, so it is set here, for cancelling and logging.
+	return client.Do(ctx, withRequestContext(ctx, req), response)
+}
@@ -0,0 +1,31 @@
+package helper
+
+import "sync"
+
+// Claims records which kind of resource manages something, so two kinds of resource don't fight over it.
+// Claims are made from CustomizeDiff, so conflicts show up at plan time. They only live as long as the provider,
+// so they only catch resources planned together, in the same configuration, and only once the key is known;
+// a resource in another configuration, or one whose server is still to be created, isn't caught.
+type Claims struct {
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+// NewClaims returns a new, empty, Claims.
+func NewClaims() *Claims {
+	return &Claims{owners: map[string]string{}}
+}
+
+// Claim claims the key for the owner, if a different owner already has it that owner is returned.
+func (c *Claims) Claim(key, owner string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if current, ok := c.owners[key]; ok && current != owner {
+		return current, false
+	}
+
+	c.owners[key] = owner
+
+	return owner, true
+}
@@ -4,15 +4,20 @@ package helper
 import (
 	"context"
 	"errors"
-	"log"
+	"net/http"
 	"net/url"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api"
 	"github.com/sitehostnz/gosh/pkg/api/job"
 	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/recorder"
 )
 
 const (
@@ -24,7 +29,8 @@ const (
 	JobStatusFailed = "Failed"
 	// JobRequestDelay is the time wait to send a new request to check the job status.
 	JobRequestDelay = 1 * time.Second
-	// JobRequestTimeout is the time to wait before timeout.
+	// JobRequestTimeout is the time to wait before timeout, when the context has no deadline.
+	// It is also the default for the timeouts of the resources that wait on jobs.
 	JobRequestTimeout = 60 * time.Minute
 	// JobRequestMinTimeout is the minimum time to wait before refreshes.
 	JobRequestMinTimeout = 3 * time.Second
@@ -32,22 +38,75 @@ const (
 	JobRequestNotFoundChecks = 60
 )
 
+// The descriptions of the provider settings, shared by the SDKv2 and framework providers so their schemas match.
+const (
+	ClientIDDescription          = "The client identifier that allows you access to your SiteHost account."
+	APIKeyDescription            = "The API Key that allows you access to your SiteHost account."
+	APIEndpointDescription       = "The HTTPS API address of the SiteHost API to use."
+	MaxRetriesDescription        = "The number of times to retry a request that fails with an error that may not happen again, such as the API being busy. Defaults to 3."
+	RetryMaxWaitDescription      = "The longest to wait between retries, as a duration such as `30s`. Defaults to `30s`."
+	RequestsPerSecondDescription = "The most requests to send to the API a second, shared by all the requests the provider makes, 0 doesn't limit them. Defaults to 10."
+)
+
+// The defaults of the retry and rate limit provider settings.
+const (
+	DefaultMaxRetries        = 3
+	DefaultRetryMaxWait      = 30 * time.Second
+	DefaultRequestsPerSecond = 10
+)
+
 // Config is a wrapper to save the configuration connection from terraform.
 type Config struct {
 	APIKey           string
 	ClientID         string
 	APIEndpoint      string
 	TerraformVersion string
+	// MaxRetries is the number of times a request is retried, RetryMaxWait the longest wait between retries.
+	MaxRetries   int
+	RetryMaxWait time.Duration
+	// RequestsPerSecond limits the rate of requests, 0 doesn't limit them.
+	RequestsPerSecond float64
+	// Transport, if set, sends the requests to the API, it defaults to the cassette in SH_CASSETTE if that is set.
+	// Requests are retried and rate limited in front of it.
+	Transport http.RoundTripper
 }
 
 // CombinedConfig is a struct with API wrapper and the Config.
 type CombinedConfig struct {
 	Client *api.Client
 	Config *Config
+	// FirewallClaims records whether sitehost_server or sitehost_server_firewall manages the security groups of a server.
+	FirewallClaims *Claims
+}
+
+// Validate checks the settings needed to use the API are there.
+func (c *Config) Validate() error {
+	if c.ClientID == "" {
+		return errors.New("client_id must be set, or the SH_CLIENT_ID environment variable")
+	}
+
+	if c.APIKey == "" {
+		return errors.New("api_key must be set, or the SH_APIKEY environment variable")
+	}
+
+	if c.MaxRetries < 0 {
+		return errors.New("max_retries must not be negative")
+	}
+
+	if c.RetryMaxWait < 0 {
+		return errors.New("retry_max_wait must not be negative")
+	}
+
+	if c.RequestsPerSecond < 0 {
+		return errors.New("requests_per_second must not be negative")
+	}
+
+	return nil
 }
 
 // Client returns a new CombinedConfig instance.
-func (c *Config) Client() (*CombinedConfig, diag.Diagnostics) {
+// The requests it makes are traced to the http subsystem logger of the context, with the API key and passwords masked.
+func (c *Config) Client(ctx context.Context) (*CombinedConfig, diag.Diagnostics) {
 	client := api.NewClient(c.APIKey, c.ClientID)
 
 	client.UserAgent = "Terraform/" + c.TerraformVersion
@@ -59,21 +118,62 @@ func (c *Config) Client() (*CombinedConfig, diag.Diagnostics) {
 		}
 
 		client.BaseURL = apiURL
-		log.Printf("[INFO] SiteHost Client configured for URL: %s", client.BaseURL.String())
+		tflog.Info(ctx, "SiteHost client configured", map[string]any{"url": client.BaseURL.String()})
+	}
+
+	transport := c.Transport
+	if transport == nil {
+		cassette, err := recorder.NewFromEnv()
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
+		transport = http.DefaultTransport
+		if cassette != nil {
+			tflog.Info(ctx, "SiteHost client using cassette", map[string]any{"cassette": os.Getenv(recorder.CassetteEnv)})
+			transport = cassette
+		}
+	}
+
+	logger := newRequestLogger(ctx, c.APIKey)
+	transport = newRetryTransport(logger, &traceTransport{logger: logger, transport: transport}, c.MaxRetries, c.RetryMaxWait, c.RequestsPerSecond)
+
+	if err := setHTTPClient(client, &http.Client{Transport: transport}); err != nil {
+		return nil, diag.FromErr(err)
 	}
 
 	return &CombinedConfig{
-		Client: client,
-		Config: c,
+		Client:         client,
+		Config:         c,
+		FirewallClaims: NewClaims(),
 	}, nil
 }
 
+// JobTimeouts returns the timeouts for a resource that waits on jobs, they default to JobRequestTimeout.
+func JobTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(JobRequestTimeout),
+		Update: schema.DefaultTimeout(JobRequestTimeout),
+		Delete: schema.DefaultTimeout(JobRequestTimeout),
+	}
+}
+
 // WaitForJob is a function to check the Job status in a refresh function.
-func WaitForJob(client *api.Client, aJob models.Job) error {
+// It waits until the deadline of the context, which terraform sets from the resource timeouts, and stops if the context is cancelled.
+func WaitForJob(ctx context.Context, client *api.Client, aJob models.Job) error {
+	timeout := JobRequestTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	ctx = LogContext(ctx, LogJob)
+	ctx = tflog.SubsystemSetField(ctx, LogJob, "job_id", aJob.ID)
+	ctx = tflog.SubsystemSetField(ctx, LogJob, "job_type", aJob.Type)
+	tflog.SubsystemDebug(ctx, LogJob, "Waiting for job", map[string]any{"timeout": timeout.String()})
+
 	var (
 		pending   = JobStatusPending
 		target    = JobStatusCompleted
-		ctx       = context.Background()
 		refreshFn = func() (result any, state string, err error) {
 			client := job.New(client)
 
@@ -89,9 +189,16 @@ func WaitForJob(client *api.Client, aJob models.Job) error {
 				return nil, "", errors.New("An error has occurred with a message: " + j.Msg)
 			}
 
+			tflog.SubsystemTrace(ctx, LogJob, "Job state", map[string]any{"state": j.Return.State})
+
 			switch j.Return.State {
 			case JobStatusFailed:
-				return j, JobStatusFailed, nil
+				return j, JobStatusFailed, &JobFailedError{
+					ID:      aJob.ID,
+					Type:    aJob.Type,
+					Message: failureMessage(j.Return.Logs),
+					Logs:    j.Return.Logs,
+				}
 			case target:
 				return j, target, nil
 			default:
@@ -105,10 +212,28 @@ func WaitForJob(client *api.Client, aJob models.Job) error {
 		Refresh:        refreshFn,
 		Target:         []string{target},
 		Delay:          JobRequestDelay,
-		Timeout:        JobRequestTimeout,
+		Timeout:        timeout,
 		MinTimeout:     JobRequestMinTimeout,
 		NotFoundChecks: JobRequestNotFoundChecks,
 	}).WaitForStateContext(ctx)
+	if err != nil {
+		tflog.SubsystemError(ctx, LogJob, "Job did not complete", map[string]any{"error": err.Error()})
+		return err
+	}
+
+	tflog.SubsystemDebug(ctx, LogJob, "Job completed")
+
+	return nil
+}
+
+// failureMessage returns why a job failed, the job has no field for it, so it is the last error in its logs.
+// The message of the response is no help, it is "Successful" as long as the job could be read.
+func failureMessage(logs []models.Log) string {
+	for i := len(logs) - 1; i >= 0; i-- {
+		if strings.EqualFold(logs[i].Level, "error") {
+			return logs[i].Message
+		}
+	}
 
-	return err
+	return ""
 }
@@ -0,0 +1,44 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sitehostnz/gosh/pkg/models"
+)
+
+func TestWaitForJobFailed(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": true, "msg": "Successful", "return": {"state": "Failed", "logs": [
+			{"date": "2025-06-18 10:00:00", "level": "info", "message": "Stopping server"},
+			{"date": "2025-06-18 10:00:01", "level": "error", "message": "Server did not stop"}
+		]}}`))
+	}))
+	t.Cleanup(api.Close)
+
+	config := Config{ClientID: "1", APIKey: "key", APIEndpoint: api.URL + "/1.5/", MaxRetries: DefaultMaxRetries}
+
+	conf, diags := config.Client(context.Background())
+	if diags.HasError() {
+		t.Fatalf("error creating client: %v", diags)
+	}
+
+	err := WaitForJob(context.Background(), conf.Client, models.Job{ID: 7, Type: "daemon"})
+
+	var failed *JobFailedError
+	if !errors.As(err, &failed) {
+		t.Fatalf("got %v, want a JobFailedError", err)
+	}
+
+	if failed.Message != "Server did not stop" {
+		t.Errorf("got message %q, want the last error in the logs", failed.Message)
+	}
+
+	if len(failed.Logs) != 2 {
+		t.Errorf("got %d log lines, want 2", len(failed.Logs))
+	}
+}
@@ -0,0 +1,67 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sitehostnz/gosh/pkg/models"
+)
+
+// notFoundMessage matches the whole message the SiteHost API gives when a GET endpoint can't find what was asked for,
+// e.g. `Server mock1 not found` or `Stack app not found on server mock1`. It is anchored, so an error that only mentions
+// something missing, like a missing image in a validation message, is not taken to mean the resource is gone.
+var notFoundMessage = regexp.MustCompile(`(?i)^(server|stack|domain|record|ssh key|ssh user|security group|database|database user) \S+ not found( on server \S+)?\.?$`)
+
+// IsNotFound checks if an error from the SiteHost API means the thing asked for does not exist,
+// either with a 404, or with the not found message of the endpoint.
+func IsNotFound(err error) bool {
+	var errorResponse *models.ErrorResponse
+	if !errors.As(err, &errorResponse) {
+		return false
+	}
+
+	if errorResponse.Response != nil && errorResponse.Response.StatusCode == http.StatusNotFound {
+		return true
+	}
+
+	return notFoundMessage.MatchString(strings.TrimSpace(errorResponse.Message))
+}
+
+// RemoveFromState clears the ID of a resource that was deleted out-of-band, so Terraform plans to recreate it.
+func RemoveFromState(ctx context.Context, d *schema.ResourceData, resource string) diag.Diagnostics {
+	tflog.Warn(ctx, "Not found, removing from state", map[string]any{"resource": resource, "id": d.Id()})
+	d.SetId("")
+
+	return nil
+}
+
+// JobFailedError is returned by WaitForJob when a job fails, with the details the job API gives us about why.
+type JobFailedError struct {
+	ID      int
+	Type    string
+	Message string
+	Logs    []models.Log
+}
+
+// Error returns the job, the message and the log lines of the job, one per line.
+func (e *JobFailedError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "job %d (%s) failed", e.ID, e.Type)
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+
+	for _, l := range e.Logs {
+		fmt.Fprintf(&b, "\n%s [%s] %s", l.Date, l.Level, l.Message)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sitehostnz/gosh/pkg/api"
+)
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   bool
+	}{
+		{name: "server", status: http.StatusNotFound, body: `{"status": false, "msg": "Server mock1 not found", "return": null}`, want: true},
+		{name: "stack", status: http.StatusNotFound, body: `{"status": false, "msg": "Stack app not found on server mock1", "return": null}`, want: true},
+		{name: "domain", status: http.StatusNotFound, body: `{"status": false, "msg": "Domain example.com not found", "return": null}`, want: true},
+		{name: "record", status: http.StatusNotFound, body: `{"status": false, "msg": "Record 123 not found", "return": null}`, want: true},
+		{name: "server without a 404", status: http.StatusOK, body: `{"status": false, "msg": "Server mock1 not found", "return": null}`, want: true},
+		{name: "stack without a 404", status: http.StatusOK, body: `{"status": false, "msg": "Stack app not found on server mock1", "return": null}`, want: true},
+		{name: "missing image", status: http.StatusBadRequest, body: `{"status": false, "msg": "Invalid image, image ubuntu-noble does not exist", "return": null}`},
+		{name: "validation", status: http.StatusBadRequest, body: `{"status": false, "msg": "No such product XENFOO, the product code could not be found", "return": null}`},
+		{name: "missing ssh key in a request", status: http.StatusOK, body: `{"status": false, "msg": "Unable to provision server: SSH key 12 not found", "return": null}`},
+		{name: "server error", status: http.StatusInternalServerError, body: `{"status": false, "msg": "Internal server error", "return": null}`},
+		{name: "not json", status: http.StatusBadGateway, body: `<html>Bad Gateway</html>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := api.CheckResponse(&http.Response{StatusCode: tt.status}, []byte(tt.body))
+			if err == nil {
+				t.Fatal("CheckResponse() returned no error")
+			}
+
+			// the resources wrap the error before checking it.
+			if got := IsNotFound(fmt.Errorf("error retrieving: %w", err)); got != tt.want {
+				t.Errorf("IsNotFound(%s) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+
+	if IsNotFound(errors.New("Server mock1 not found")) {
+		t.Error("IsNotFound() matched an error that didn't come from the API")
+	}
+}
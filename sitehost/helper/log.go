@@ -0,0 +1,56 @@
+package helper
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// The subsystems the provider logs to, each can have its own level with TF_LOG_PROVIDER_SITEHOST_<SUBSYSTEM>, such as TF_LOG_PROVIDER_SITEHOST_HTTP.
+const (
+	LogServer = "server"
+	LogStack  = "stack"
+	LogDNS    = "dns"
+	LogSSH    = "ssh"
+	LogJob    = "job"
+	LogHTTP   = "http"
+)
+
+// logEnv is the start of the environment variables that set the levels of the subsystems.
+const logEnv = "TF_LOG_PROVIDER_SITEHOST"
+
+// maskedValue replaces secrets in what is logged.
+const maskedValue = "***"
+
+// passwordField matches a JSON field with password in its name, and a string value.
+var passwordField = regexp.MustCompile(`("[a-z_]*password[a-z_]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// LogContext returns the context with the logger for the subsystem, which is at the level of the provider
+// unless TF_LOG_PROVIDER_SITEHOST_<SUBSYSTEM> is set, and has the fields terraform sets, such as the resource type.
+func LogContext(ctx context.Context, subsystem string) context.Context {
+	return tflog.NewSubsystem(ctx, subsystem, tflog.WithLevelFromEnv(logEnv, subsystem), tflog.WithRootFields())
+}
+
+// maskQuery masks the API key and passwords in form encoded values, such as a query or a request body.
+func maskQuery(query string) string {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return maskedValue
+	}
+
+	for key := range values {
+		if key == "apikey" || strings.Contains(strings.ToLower(key), "password") {
+			values.Set(key, maskedValue)
+		}
+	}
+
+	return strings.ReplaceAll(values.Encode(), url.QueryEscape(maskedValue), maskedValue)
+}
+
+// maskBody masks passwords in a JSON body.
+func maskBody(body string) string {
+	return passwordField.ReplaceAllString(body, `${1}"`+maskedValue+`"`)
+}
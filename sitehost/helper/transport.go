@@ -0,0 +1,298 @@
+package helper
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// RetryBaseWait is the wait before the first retry, it doubles with each retry after that, up to the retry max wait.
+const RetryBaseWait = 500 * time.Millisecond
+
+type (
+	// retryTransport sends requests with a transport, taking a token from the limiter first,
+	// and retries them when they fail in a way that may not happen again.
+	retryTransport struct {
+		logger     requestLogger
+		transport  http.RoundTripper
+		limiter    *tokenBucket
+		maxRetries int
+		maxWait    time.Duration
+	}
+
+	// traceTransport logs the requests sent with a transport, and their responses, at trace level,
+	// with the API key and passwords masked.
+	traceTransport struct {
+		logger    requestLogger
+		transport http.RoundTripper
+	}
+
+	// requestLogger picks the context to log a request with.
+	requestLogger struct {
+		// ctx is the context the client was made with, for the requests the gosh client makes, as it doesn't pass on its context.
+		ctx    context.Context
+		apiKey string
+	}
+
+	// requestContextKey marks the context of a request as one with the logger of the resource it is for.
+	requestContextKey struct{}
+
+	// tokenBucket limits the rate of requests, it starts full and holds up to a second of tokens.
+	tokenBucket struct {
+		mu     sync.Mutex
+		rate   float64
+		burst  float64
+		tokens float64
+		last   time.Time
+	}
+)
+
+// newRetryTransport returns a transport that retries requests up to maxRetries times, and sends no more than requestsPerSecond,
+// all the requests share the one limit. A requestsPerSecond of 0 doesn't limit the requests.
+func newRetryTransport(logger requestLogger, transport http.RoundTripper, maxRetries int, maxWait time.Duration, requestsPerSecond float64) *retryTransport {
+	t := &retryTransport{logger: logger, transport: transport, maxRetries: maxRetries, maxWait: maxWait}
+	if requestsPerSecond > 0 {
+		t.limiter = newTokenBucket(requestsPerSecond)
+	}
+
+	return t
+}
+
+// newRequestLogger returns a requestLogger that logs to the http subsystem, with the API key masked.
+func newRequestLogger(ctx context.Context, apiKey string) requestLogger {
+	l := requestLogger{apiKey: apiKey}
+	l.ctx = l.with(ctx)
+
+	return l
+}
+
+// withRequestContext returns the request with the context, so it is logged with the fields of the resource it is for.
+func withRequestContext(ctx context.Context, req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(ctx, requestContextKey{}, true))
+}
+
+// context returns the context to log the request with, which is its own when it was made by Post or Get.
+func (l requestLogger) context(req *http.Request) context.Context {
+	if req.Context().Value(requestContextKey{}) == nil {
+		return l.ctx
+	}
+
+	return l.with(req.Context())
+}
+
+// with adds the http subsystem to the context, with the API key masked.
+func (l requestLogger) with(ctx context.Context) context.Context {
+	ctx = LogContext(ctx, LogHTTP)
+	if l.apiKey != "" {
+		ctx = tflog.SubsystemMaskLogStrings(ctx, LogHTTP, l.apiKey)
+	}
+
+	return ctx
+}
+
+// RoundTrip sends the request, retrying it with jittered exponential backoff.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+
+		if err := req.Body.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		out := req.Clone(req.Context())
+		if body != nil {
+			out.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := t.transport.RoundTrip(out)
+		if attempt >= t.maxRetries || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := backoff(attempt, t.maxWait, resp)
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		// the path is logged, not the URL, as the query has the API key.
+		tflog.SubsystemWarn(t.logger.context(req), LogHTTP, "Retrying request", map[string]any{
+			"method":      req.Method,
+			"path":        req.URL.Path,
+			"wait":        wait.String(),
+			"retry":       attempt + 1,
+			"max_retries": t.maxRetries,
+			"reason":      reason,
+		})
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// RoundTrip sends the request, logging it and the response.
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+
+		if err := req.Body.Close(); err != nil {
+			return nil, err
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	ctx := t.logger.context(req)
+
+	tflog.SubsystemTrace(ctx, LogHTTP, "Sending request", map[string]any{
+		"method": req.Method,
+		"path":   req.URL.Path,
+		"query":  maskQuery(req.URL.RawQuery),
+		"body":   maskQuery(string(body)),
+	})
+
+	start := time.Now()
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		tflog.SubsystemTrace(ctx, LogHTTP, "Request failed", map[string]any{
+			"method":   req.Method,
+			"path":     req.URL.Path,
+			"duration": time.Since(start).String(),
+			"error":    err.Error(),
+		})
+
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if closeErr := resp.Body.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	tflog.SubsystemTrace(ctx, LogHTTP, "Received response", map[string]any{
+		"method":   req.Method,
+		"path":     req.URL.Path,
+		"status":   resp.StatusCode,
+		"duration": time.Since(start).String(),
+		"body":     maskBody(string(data)),
+	})
+
+	return resp, nil
+}
+
+// retryable checks whether the request failed in a way that may not happen again.
+// Requests that change things are only retried when the API says it didn't handle them, so they aren't made twice.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil && isIdempotent(req)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotent(req)
+	default:
+		return false
+	}
+}
+
+// isIdempotent checks whether the request can be made more than once, the SiteHost API makes changes with POST requests.
+func isIdempotent(req *http.Request) bool {
+	return req.Method == http.MethodGet || req.Method == http.MethodHead
+}
+
+// backoff returns the wait before a retry, doubling with each retry up to maxWait, with jitter so retries of concurrent requests spread out.
+// A Retry-After from the API is waited for, if it is longer, up to maxWait.
+func backoff(attempt int, maxWait time.Duration, resp *http.Response) time.Duration {
+	wait := maxWait
+	if attempt < 30 && RetryBaseWait<<attempt < maxWait {
+		wait = RetryBaseWait << attempt
+	}
+
+	wait = wait/2 + rand.N(wait/2+1) //nolint:gosec // jitter doesn't need a secure random number.
+
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			wait = max(wait, min(time.Duration(seconds)*time.Second, maxWait))
+		}
+	}
+
+	return wait
+}
+
+// newTokenBucket returns a full bucket with the rate.
+func newTokenBucket(rate float64) *tokenBucket {
+	burst := max(rate, 1)
+
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait takes a token from the bucket, waiting for one if it is empty. A nil bucket doesn't limit.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+
+			return nil
+		}
+
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
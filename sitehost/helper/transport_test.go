@@ -0,0 +1,117 @@
+package helper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/sitehostnz/gosh/pkg/api/ssh/key"
+)
+
+func TestClientRetriesRequests(t *testing.T) {
+	var calls [2]atomic.Int32
+
+	endpoints := make([]string, len(calls))
+	for i := range calls {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			// the first request to each API is unavailable, so it is retried.
+			if calls[i].Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"status": true, "msg": "Successful", "return": {"id": "%d", "label": "key %d"}}`, i, i)
+		}))
+		t.Cleanup(api.Close)
+
+		endpoints[i] = api.URL + "/1.5/"
+	}
+
+	for i, endpoint := range endpoints {
+		config := Config{ClientID: "1", APIKey: "key", APIEndpoint: endpoint, MaxRetries: 1, RetryMaxWait: time.Millisecond}
+
+		conf, diags := config.Client(context.Background())
+		if diags.HasError() {
+			t.Fatalf("error creating client: %v", diags)
+		}
+
+		response, err := key.New(conf.Client).Get(context.Background(), key.GetRequest{ID: "1"})
+		if err != nil {
+			t.Fatalf("error getting key from API %d: %s", i, err)
+		}
+
+		if want := fmt.Sprintf("key %d", i); response.Return.Label != want {
+			t.Errorf("got %q from API %d, want %q", response.Return.Label, i, want)
+		}
+
+		if got := calls[i].Load(); got != 2 {
+			t.Errorf("API %d got %d requests, want 2", i, got)
+		}
+	}
+
+	// the clients have transports of their own, nothing is registered with the default one.
+	if resp, err := http.DefaultClient.Get("sitehost://client-1/1.5/"); err == nil {
+		_ = resp.Body.Close()
+		t.Error("http.DefaultTransport has a sitehost protocol registered")
+	}
+}
+
+func TestPostLogsWithRequestContext(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"status": true, "msg": "Successful", "return": {"job": {"id": 1, "type": "daemon"}}}`)
+	}))
+	t.Cleanup(api.Close)
+
+	var output bytes.Buffer
+
+	config := Config{ClientID: "1", APIKey: "secret-key", APIEndpoint: api.URL + "/1.5/"}
+
+	conf, diags := config.Client(tflogtest.RootLogger(context.Background(), &output))
+	if diags.HasError() {
+		t.Fatalf("error creating client: %v", diags)
+	}
+
+	// the request is made with a context of its own, as a resource would, so it is logged with its fields.
+	ctx := tflog.SetField(tflogtest.RootLogger(context.Background(), &output), "server_name", "ch-server1")
+
+	var response JobResponse
+	if err := Post(ctx, conf.Client, "server/start.json", []string{"name"}, url.Values{"name": {"ch-server1"}}, &response); err != nil {
+		t.Fatalf("error posting: %s", err)
+	}
+
+	if strings.Contains(output.String(), "secret-key") {
+		t.Errorf("the API key was logged: %s", output.String())
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("error decoding logs: %s", err)
+	}
+
+	var traced int
+	for _, entry := range entries {
+		if entry["@module"] != "provider."+LogHTTP {
+			continue
+		}
+
+		traced++
+		if entry["server_name"] != "ch-server1" {
+			t.Errorf("%q was logged without the fields of the request context: %v", entry["@message"], entry)
+		}
+	}
+
+	if traced != 2 {
+		t.Errorf("got %d http log entries, want 2", traced)
+	}
+}
@@ -1,5 +1,11 @@
 package helper
 
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
 // Filter filters a collection.
 func Filter[T any](ss []T, test func(T) bool) (ret []T) {
 	for _, s := range ss {
@@ -38,3 +44,32 @@ func Has[T any](ss []T, test func(T) bool) (ret bool) {
 	}
 	return
 }
+
+// ToStringList converts a terraform list into a list of strings.
+func ToStringList(list any) []string {
+	values, ok := list.([]any)
+	if !ok {
+		return nil
+	}
+
+	return Map(values, func(v any) string { return fmt.Sprint(v) })
+}
+
+// IsConfigured checks if an attribute or block is in the configuration, rather than only coming from the state.
+// A block that isn't in the configuration is empty rather than null, so it only counts when there is at least one.
+func IsConfigured(config cty.Value, key string) bool {
+	if config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() || !config.Type().HasAttribute(key) {
+		return false
+	}
+
+	v := config.GetAttr(key)
+	if v.IsNull() {
+		return false
+	}
+
+	if v.IsKnown() && v.CanIterateElements() {
+		return v.LengthInt() > 0
+	}
+
+	return true
+}
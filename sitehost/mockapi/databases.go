@@ -0,0 +1,322 @@
+package mockapi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sitehostnz/gosh/pkg/models"
+)
+
+// addDatabaseRoutes adds the database, database user and grant endpoints.
+func (s *Server) addDatabaseRoutes() {
+	s.routes["cloud/db/add.json"] = s.addDatabase
+	s.routes["cloud/db/get.json"] = s.getDatabase
+	s.routes["cloud/db/list_all.json"] = s.listDatabases
+	s.routes["cloud/db/update.json"] = s.updateDatabase
+	s.routes["cloud/db/delete.json"] = s.deleteDatabase
+	s.routes["cloud/db/user/add.json"] = s.addDatabaseUser
+	s.routes["cloud/db/user/get.json"] = s.getDatabaseUser
+	s.routes["cloud/db/user/list_all.json"] = s.listDatabaseUsers
+	s.routes["cloud/db/user/update.json"] = s.updateDatabaseUser
+	s.routes["cloud/db/user/delete.json"] = s.deleteDatabaseUser
+	s.routes["cloud/db/grant/add.json"] = s.setGrant
+	s.routes["cloud/db/grant/update.json"] = s.setGrant
+	s.routes["cloud/db/grant/delete.json"] = s.deleteGrant
+}
+
+// databaseKey is the key of a database, or a database user, which are unique to a MySQL host on a server.
+func databaseKey(server, mysqlHost, name string) string {
+	return server + "/" + mysqlHost + "/" + name
+}
+
+// findDatabase returns the database the request is for.
+func (s *Server) findDatabase(r request) (*models.Database, error) {
+	database, ok := s.databases[databaseKey(r.Get("server_name"), r.Get("mysql_host"), r.Get("database"))]
+	if !ok {
+		return nil, notFound("Database %s not found", r.Get("database"))
+	}
+
+	return database, nil
+}
+
+// findDatabaseUser returns the database user the request is for.
+func (s *Server) findDatabaseUser(r request) (*models.DatabaseUser, error) {
+	user, ok := s.databaseUsers[databaseKey(r.Get("server_name"), r.Get("mysql_host"), r.Get("username"))]
+	if !ok {
+		return nil, notFound("Database user %s not found", r.Get("username"))
+	}
+
+	return user, nil
+}
+
+// addDatabase creates a database.
+func (s *Server) addDatabase(r request) (any, error) {
+	srv, err := s.findServer(r.Get("server_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	mysqlHost, err := r.required("mysql_host")
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := r.required("database")
+	if err != nil {
+		return nil, err
+	}
+
+	key := databaseKey(srv.Name, mysqlHost, name)
+	if _, ok := s.databases[key]; ok {
+		return nil, invalid("Database %s already exists", name)
+	}
+
+	now := timestamp()
+	s.databases[key] = &models.Database{
+		ID:          strconv.Itoa(s.newID()),
+		DBName:      name,
+		MySQLHost:   mysqlHost,
+		Size:        0,
+		ClientID:    s.clientID,
+		ServerID:    srv.Name,
+		Pending:     "0",
+		IsMissing:   "0",
+		DateAdded:   now,
+		DateUpdated: now,
+		ServerName:  srv.Name,
+		ServerLabel: srv.Label,
+		ServerIP:    srv.Ips[0].IPAddr,
+		ServerOwner: true,
+		Container:   r.Get("container"),
+		Grants:      []models.Grant{},
+	}
+
+	return jobReturn(s.newJob()), nil
+}
+
+// getDatabase returns a database, with the grants on it.
+func (s *Server) getDatabase(r request) (any, error) {
+	return s.findDatabase(r)
+}
+
+// listDatabases returns the databases, filtered by server, MySQL host and name.
+func (s *Server) listDatabases(r request) (any, error) {
+	databases := make([]models.Database, 0, len(s.databases))
+	for _, key := range sortedKeys(s.databases) {
+		database := s.databases[key]
+		if matches(r, "filters[server_name]", database.ServerName) &&
+			matches(r, "filters[mysql_host]", database.MySQLHost) &&
+			matches(r, "filters[db_name]", database.DBName) {
+			databases = append(databases, *database)
+		}
+	}
+
+	return page(r, s.pageSize, databases), nil
+}
+
+// updateDatabase changes the container a database is backed up with.
+func (s *Server) updateDatabase(r request) (any, error) {
+	database, err := s.findDatabase(r)
+	if err != nil {
+		return nil, err
+	}
+
+	database.Container = r.Get("params[container]")
+	database.DateUpdated = timestamp()
+
+	return nil, nil
+}
+
+// deleteDatabase deletes a database, along with the grants on it.
+func (s *Server) deleteDatabase(r request) (any, error) {
+	database, err := s.findDatabase(r)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(s.databases, databaseKey(database.ServerName, database.MySQLHost, database.DBName))
+
+	for _, user := range s.databaseUsers {
+		if user.ServerName == database.ServerName && user.MysqlHost == database.MySQLHost {
+			user.Grants = withoutGrant(user.Grants, database.DBName, user.Username)
+		}
+	}
+
+	return jobReturn(s.newJob()), nil
+}
+
+// addDatabaseUser creates a database user, with grants on a database if they are given.
+func (s *Server) addDatabaseUser(r request) (any, error) {
+	srv, err := s.findServer(r.Get("server_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	mysqlHost, err := r.required("mysql_host")
+	if err != nil {
+		return nil, err
+	}
+
+	username, err := r.required("username")
+	if err != nil {
+		return nil, err
+	}
+
+	key := databaseKey(srv.Name, mysqlHost, username)
+	if _, ok := s.databaseUsers[key]; ok {
+		return nil, invalid("Database user %s already exists", username)
+	}
+
+	now := timestamp()
+	s.databaseUsers[key] = &models.DatabaseUser{
+		Username:    username,
+		Password:    r.Get("password"),
+		MysqlHost:   mysqlHost,
+		ServerID:    srv.Name,
+		ClientID:    s.clientID,
+		IsMissing:   "0",
+		DateAdded:   now,
+		DateUpdated: now,
+		ServerName:  srv.Name,
+		ServerLabel: srv.Label,
+		Grants:      []models.Grant{},
+	}
+
+	if r.Get("database") != "" {
+		if _, err := s.findDatabase(r); err != nil {
+			return nil, err
+		}
+
+		s.putGrant(srv.Name, mysqlHost, r.Get("database"), username, r.Values["grants[]"])
+	}
+
+	return jobReturn(s.newJob()), nil
+}
+
+// getDatabaseUser returns a database user, with their grants.
+func (s *Server) getDatabaseUser(r request) (any, error) {
+	return s.findDatabaseUser(r)
+}
+
+// listDatabaseUsers returns the database users, filtered by server, MySQL host and username.
+func (s *Server) listDatabaseUsers(r request) (any, error) {
+	users := make([]models.DatabaseUser, 0, len(s.databaseUsers))
+	for _, key := range sortedKeys(s.databaseUsers) {
+		user := s.databaseUsers[key]
+		if matches(r, "filters[server_name]", user.ServerName) &&
+			matches(r, "filters[mysql_host]", user.MysqlHost) &&
+			matches(r, "filters[username]", user.Username) {
+			users = append(users, *user)
+		}
+	}
+
+	return page(r, s.pageSize, users), nil
+}
+
+// updateDatabaseUser changes the password of a database user.
+func (s *Server) updateDatabaseUser(r request) (any, error) {
+	user, err := s.findDatabaseUser(r)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = valueOr(r.Get("password"), user.Password)
+	user.DateUpdated = timestamp()
+
+	return jobReturn(s.newJob()), nil
+}
+
+// deleteDatabaseUser deletes a database user, along with their grants.
+func (s *Server) deleteDatabaseUser(r request) (any, error) {
+	user, err := s.findDatabaseUser(r)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(s.databaseUsers, databaseKey(user.ServerName, user.MysqlHost, user.Username))
+
+	for _, database := range s.databases {
+		if database.ServerName == user.ServerName && database.MySQLHost == user.MysqlHost {
+			database.Grants = withoutGrant(database.Grants, database.DBName, user.Username)
+		}
+	}
+
+	return jobReturn(s.newJob()), nil
+}
+
+// setGrant adds or replaces the grants of a user on a database.
+func (s *Server) setGrant(r request) (any, error) {
+	database, err := s.findDatabase(r)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.findDatabaseUser(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s.putGrant(database.ServerName, database.MySQLHost, database.DBName, user.Username, r.Values["grants[]"])
+
+	return jobReturn(s.newJob()), nil
+}
+
+// deleteGrant removes the grants of a user on a database.
+func (s *Server) deleteGrant(r request) (any, error) {
+	database, err := s.findDatabase(r)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.findDatabaseUser(r)
+	if err != nil {
+		return nil, err
+	}
+
+	database.Grants = withoutGrant(database.Grants, database.DBName, user.Username)
+	user.Grants = withoutGrant(user.Grants, database.DBName, user.Username)
+
+	return jobReturn(s.newJob()), nil
+}
+
+// putGrant sets the grants of a user on a database, on both the database and the user.
+func (s *Server) putGrant(server, mysqlHost, database, username string, grants []string) {
+	now := timestamp()
+	grant := models.Grant{
+		DBName:      database,
+		Username:    username,
+		Host:        "%",
+		MySQLHost:   mysqlHost,
+		Grants:      append([]string{}, grants...),
+		IsMissing:   "0",
+		DateAdded:   now,
+		DateUpdated: now,
+	}
+
+	if d, ok := s.databases[databaseKey(server, mysqlHost, database)]; ok {
+		d.Grants = append(withoutGrant(d.Grants, database, username), grant)
+	}
+
+	if u, ok := s.databaseUsers[databaseKey(server, mysqlHost, username)]; ok {
+		u.Grants = append(withoutGrant(u.Grants, database, username), grant)
+	}
+}
+
+// withoutGrant returns the grants, without the grant of the user on the database.
+func withoutGrant(grants []models.Grant, database, username string) []models.Grant {
+	kept := make([]models.Grant, 0, len(grants))
+	for _, g := range grants {
+		if g.DBName != database || g.Username != username {
+			kept = append(kept, g)
+		}
+	}
+
+	return kept
+}
+
+// matches checks the value matches the filter, if the filter is set.
+func matches(r request, filter string, value string) bool {
+	f := r.Get(filter)
+
+	return f == "" || strings.EqualFold(f, value)
+}
@@ -0,0 +1,230 @@
+package mockapi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/gosh/pkg/net"
+)
+
+// defaultTTL is the TTL records get when one isn't given.
+const defaultTTL = "3600"
+
+// zone is a DNS zone, with its records in the order they were added.
+type zone struct {
+	models.DNSZone
+
+	records []models.DNSRecord
+}
+
+// addDNSRoutes adds the DNS endpoints.
+func (s *Server) addDNSRoutes() {
+	s.routes["dns/create_domain.json"] = s.createZone
+	s.routes["dns/delete_domain.json"] = s.deleteZone
+	s.routes["dns/search_domains.json"] = s.searchZones
+	s.routes["dns/list_domains.json"] = s.listZones
+	s.routes["dns/list_records.json"] = s.listRecords
+	s.routes["dns/add_record.json"] = s.addRecord
+	s.routes["dns/update_record.json"] = s.updateRecord
+	s.routes["dns/delete_record.json"] = s.deleteRecord
+}
+
+// findZone returns the zone for the domain.
+func (s *Server) findZone(domain string) (*zone, error) {
+	z, ok := s.zones[strings.ToLower(domain)]
+	if !ok {
+		return nil, notFound("Domain %s not found", domain)
+	}
+
+	return z, nil
+}
+
+// createZone creates a zone, with the SOA record SiteHost adds to new zones.
+func (s *Server) createZone(r request) (any, error) {
+	domain, err := r.required("domain")
+	if err != nil {
+		return nil, err
+	}
+
+	domain = strings.ToLower(domain)
+	if _, ok := s.zones[domain]; ok {
+		return nil, invalid("Domain %s already exists", domain)
+	}
+
+	z := &zone{DNSZone: models.DNSZone{Name: domain, ClientID: s.clientID, TemplateID: "0", Pending: "0"}}
+	z.records = append(z.records, s.newRecord(domain, "SOA", domain, "ns1.sitehost.co.nz. hostmaster.sitehost.co.nz. 1 10800 3600 604800 3600", "0", defaultTTL))
+
+	s.zones[domain] = z
+
+	return map[string]any{"is_migration": false}, nil
+}
+
+// deleteZone deletes a zone and its records.
+func (s *Server) deleteZone(r request) (any, error) {
+	z, err := s.findZone(r.Get("domain"))
+	if err != nil {
+		return nil, err
+	}
+
+	delete(s.zones, z.Name)
+
+	return nil, nil
+}
+
+// searchZones returns the zones with the domain, like the API it returns nothing, rather than an error, when there are none.
+func (s *Server) searchZones(r request) (any, error) {
+	domain := strings.ToLower(r.Get("query[domain]"))
+
+	zones := make([]map[string]string, 0)
+	for _, name := range sortedKeys(s.zones) {
+		if name == domain {
+			zones = append(zones, map[string]string{"name": name})
+		}
+	}
+
+	return zones, nil
+}
+
+// listZones returns the zones, filtered by domain.
+func (s *Server) listZones(r request) (any, error) {
+	domain := strings.ToLower(r.Get("filters[domain]"))
+
+	zones := make([]models.DNSZone, 0, len(s.zones))
+	for _, name := range sortedKeys(s.zones) {
+		if domain == "" || strings.Contains(name, domain) {
+			zones = append(zones, s.zones[name].DNSZone)
+		}
+	}
+
+	return page(r, s.pageSize, zones), nil
+}
+
+// listRecords returns the records in a zone.
+func (s *Server) listRecords(r request) (any, error) {
+	z, err := s.findZone(r.Get("domain"))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]models.DNSRecord, len(z.records))
+	copy(records, z.records)
+
+	return records, nil
+}
+
+// addRecord adds a record to a zone.
+func (s *Server) addRecord(r request) (any, error) {
+	z, err := s.findZone(r.Get("domain"))
+	if err != nil {
+		return nil, err
+	}
+
+	recordType, err := r.required("type")
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := r.required("content")
+	if err != nil {
+		return nil, err
+	}
+
+	record := s.newRecord(z.Name, strings.ToUpper(recordType), r.Get("name"), content, r.Get("prio"), r.Get("ttl"))
+	z.records = append(z.records, record)
+
+	return map[string]string{"id": record.ID}, nil
+}
+
+// updateRecord replaces a record in a zone.
+func (s *Server) updateRecord(r request) (any, error) {
+	z, err := s.findZone(r.Get("domain"))
+	if err != nil {
+		return nil, err
+	}
+
+	i := z.record(r.Get("record_id"))
+	if i < 0 {
+		return nil, notFound("Record %s not found", r.Get("record_id"))
+	}
+
+	record := &z.records[i]
+	record.Name = recordName(r.Get("name"), z.Name)
+	record.Content = r.Get("content")
+	record.Priority = valueOr(r.Get("prio"), "0")
+	record.ChangeDate = timestamp()
+
+	if recordType := r.Get("type"); recordType != "" {
+		record.Type = strings.ToUpper(recordType)
+	}
+
+	if ttl := r.Get("ttl"); ttl != "" {
+		record.TTL = ttl
+	}
+
+	return nil, nil
+}
+
+// deleteRecord deletes a record from a zone.
+func (s *Server) deleteRecord(r request) (any, error) {
+	z, err := s.findZone(r.Get("domain"))
+	if err != nil {
+		return nil, err
+	}
+
+	i := z.record(r.Get("record_id"))
+	if i < 0 {
+		return nil, notFound("Record %s not found", r.Get("record_id"))
+	}
+
+	z.records = append(z.records[:i], z.records[i+1:]...)
+
+	return nil, nil
+}
+
+// newRecord returns a new record in the domain.
+func (s *Server) newRecord(domain, recordType, name, content, priority, ttl string) models.DNSRecord {
+	return models.DNSRecord{
+		ID:         strconv.Itoa(s.newID()),
+		ClientID:   s.clientID,
+		Name:       recordName(name, domain),
+		Domain:     domain,
+		Type:       recordType,
+		Content:    content,
+		TTL:        valueOr(ttl, defaultTTL),
+		Priority:   valueOr(priority, "0"),
+		ChangeDate: timestamp(),
+		State:      "0",
+	}
+}
+
+// record returns the index of the record with the ID, or -1 if there isn't one.
+func (z *zone) record(id string) int {
+	for i, record := range z.records {
+		if record.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// recordName returns the name the API stores for a record, the fully qualified name without the trailing dot.
+// The name can be given relative to the domain, or fully qualified, with or without the trailing dot.
+func recordName(name, domain string) string {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	if name == domain || strings.HasSuffix(name, "."+domain) {
+		return name
+	}
+
+	return strings.TrimSuffix(net.ConstructFqdn(name, domain), ".")
+}
+
+// valueOr returns the value, or the fallback if the value is empty.
+func valueOr(v string, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+
+	return v
+}
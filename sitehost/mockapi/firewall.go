@@ -0,0 +1,249 @@
+package mockapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sitehostnz/gosh/pkg/api/server/firewall/securitygroups"
+)
+
+// securityGroup is a security group, with its rules.
+type securityGroup struct {
+	Name        string
+	Label       string
+	Version     int
+	DateAdded   string
+	DateUpdated string
+	RulesIn     []securitygroups.Rule
+	RulesOut    []securitygroups.Rule
+}
+
+// addFirewallRoutes adds the firewall and security group endpoints.
+func (s *Server) addFirewallRoutes() {
+	s.routes["server/firewall/get.json"] = s.getFirewall
+	s.routes["server/firewall/update.json"] = s.updateFirewall
+	s.routes["server/firewall/security_groups/add.json"] = s.addSecurityGroup
+	s.routes["server/firewall/security_groups/get.json"] = s.getSecurityGroup
+	s.routes["server/firewall/security_groups/list_all.json"] = s.listSecurityGroups
+	s.routes["server/firewall/security_groups/update.json"] = s.updateSecurityGroup
+	s.routes["server/firewall/security_groups/delete.json"] = s.deleteSecurityGroup
+}
+
+// getFirewall returns the security groups on the firewall of a server, in order.
+func (s *Server) getFirewall(r request) (any, error) {
+	srv, err := s.findServer(r.Get("server"))
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]map[string]any, 0, len(s.firewalls[srv.Name]))
+	for i, group := range s.firewalls[srv.Name] {
+		groups = append(groups, map[string]any{
+			"group":     group,
+			"pos":       strconv.Itoa(i + 1),
+			"interface": "eth0",
+			"is_ra":     false,
+		})
+	}
+
+	return groups, nil
+}
+
+// updateFirewall replaces the security groups on the firewall of a server.
+func (s *Server) updateFirewall(r request) (any, error) {
+	srv, err := s.findServer(r.Get("server"))
+	if err != nil {
+		return nil, err
+	}
+
+	groups := r.indexed("groups")
+	for _, group := range groups {
+		if _, ok := s.securityGroups[group]; !ok {
+			return nil, notFound("Security group %s not found", group)
+		}
+	}
+
+	s.firewalls[srv.Name] = groups
+
+	return jobReturn(s.newJob()), nil
+}
+
+// addSecurityGroup creates a security group, with no rules.
+func (s *Server) addSecurityGroup(r request) (any, error) {
+	label, err := r.required("label")
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("sg%d", s.newID())
+	now := timestamp()
+
+	s.securityGroups[name] = &securityGroup{
+		Name:        name,
+		Label:       label,
+		Version:     1,
+		DateAdded:   now,
+		DateUpdated: now,
+	}
+
+	return map[string]any{"name": name}, nil
+}
+
+// findSecurityGroup returns the security group with the name.
+func (s *Server) findSecurityGroup(name string) (*securityGroup, error) {
+	group, ok := s.securityGroups[name]
+	if !ok {
+		return nil, notFound("Security group %s not found", name)
+	}
+
+	return group, nil
+}
+
+// getSecurityGroup returns a security group, with its rules and the servers using it.
+func (s *Server) getSecurityGroup(r request) (any, error) {
+	group, err := s.findSecurityGroup(r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	servers := make([]map[string]string, 0)
+	for _, name := range s.securityGroupServers(group.Name) {
+		servers = append(servers, map[string]string{"name": name, "label": s.servers[name].Label})
+	}
+
+	return map[string]any{
+		"name":         group.Name,
+		"label":        group.Label,
+		"pending":      "0",
+		"is_missing":   false,
+		"date_added":   group.DateAdded,
+		"date_updated": group.DateUpdated,
+		"servers":      servers,
+		"rules": map[string]any{
+			"in":  rulesOrEmpty(group.RulesIn),
+			"out": rulesOrEmpty(group.RulesOut),
+		},
+	}, nil
+}
+
+// listSecurityGroups returns the security groups, filtered by label.
+func (s *Server) listSecurityGroups(r request) (any, error) {
+	label := r.Get("filters[label]")
+
+	groups := make([]map[string]any, 0, len(s.securityGroups))
+	for _, name := range sortedKeys(s.securityGroups) {
+		group := s.securityGroups[name]
+		if label != "" && !strings.Contains(group.Label, label) {
+			continue
+		}
+
+		groups = append(groups, map[string]any{
+			"name":         group.Name,
+			"label":        group.Label,
+			"version":      group.Version,
+			"servers":      s.securityGroupServers(group.Name),
+			"date_updated": group.DateUpdated,
+			"pending":      "0",
+			"is_missing":   false,
+		})
+	}
+
+	return page(r, s.pageSize, groups), nil
+}
+
+// updateSecurityGroup replaces the label and rules of a security group.
+func (s *Server) updateSecurityGroup(r request) (any, error) {
+	group, err := s.findSecurityGroup(r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	if label := r.Get("params[label]"); label != "" {
+		group.Label = label
+	}
+
+	group.RulesIn = r.rules("params[rules_in]", "in", "src_ip")
+	group.RulesOut = r.rules("params[rules_out]", "out", "dest_ip")
+	group.Version++
+	group.DateUpdated = timestamp()
+
+	return jobReturn(s.newJob()), nil
+}
+
+// deleteSecurityGroup deletes a security group, and takes it off the firewalls using it.
+func (s *Server) deleteSecurityGroup(r request) (any, error) {
+	group, err := s.findSecurityGroup(r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	delete(s.securityGroups, group.Name)
+
+	for server, groups := range s.firewalls {
+		kept := groups[:0]
+		for _, g := range groups {
+			if g != group.Name {
+				kept = append(kept, g)
+			}
+		}
+		s.firewalls[server] = kept
+	}
+
+	return jobReturn(s.newJob()), nil
+}
+
+// securityGroupServers returns the names of the servers with the security group on their firewall, sorted.
+func (s *Server) securityGroupServers(name string) []string {
+	servers := make([]string, 0)
+	for _, server := range sortedKeys(s.firewalls) {
+		for _, group := range s.firewalls[server] {
+			if group == name {
+				servers = append(servers, server)
+				break
+			}
+		}
+	}
+
+	return servers
+}
+
+// rules reads the rules in parameters like prefix[0][action], the ip is the source or destination depending on the direction.
+func (r request) rules(prefix string, dir string, ipKey string) []securitygroups.Rule {
+	indexes := r.indexes(prefix, "")
+
+	rules := make([]securitygroups.Rule, 0, len(indexes))
+	for pos, i := range indexes {
+		field := func(name string) string {
+			return r.Get(fmt.Sprintf("%s[%d][%s]", prefix, i, name))
+		}
+
+		rule := securitygroups.Rule{
+			Pos:      pos + 1,
+			Dir:      dir,
+			Enabled:  boolValue(field("enabled")),
+			Action:   field("action"),
+			Protocol: field("protocol"),
+			DestPort: atoi(field("dest_port")),
+		}
+
+		if ipKey == "src_ip" {
+			rule.SrcIP = field(ipKey)
+		} else {
+			rule.DestIP = field(ipKey)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// rulesOrEmpty returns the rules, or an empty list rather than null.
+func rulesOrEmpty(rules []securitygroups.Rule) []securitygroups.Rule {
+	if rules == nil {
+		return []securitygroups.Rule{}
+	}
+
+	return rules
+}
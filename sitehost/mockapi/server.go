@@ -0,0 +1,395 @@
+// Package mockapi provides an in-process fake of the SiteHost API, so the provider can be exercised without a SiteHost account.
+//
+// The fake keeps its state in memory and speaks the same form encoded requests and JSON responses as the API,
+// point the provider at it with the api_endpoint setting:
+//
+//	api := mockapi.NewServer("client-id", "api-key")
+//	defer api.Close()
+//
+//	provider "sitehost" {
+//	  client_id    = "client-id"
+//	  api_key      = "api-key"
+//	  api_endpoint = api.Endpoint()
+//	}
+//
+// Jobs complete as soon as they are created, unless FailNextJob is used.
+package mockapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sitehostnz/gosh/pkg/models"
+)
+
+const (
+	// apiVersion is the version in the path of the endpoint, the fake answers on any version.
+	apiVersion = "1.5"
+	// dateFormat is the format the API uses for dates.
+	dateFormat = "2006-01-02 15:04:05"
+)
+
+type (
+	// Server is a fake SiteHost API.
+	Server struct {
+		*httptest.Server
+
+		clientID string
+		apiKey   string
+
+		mu     sync.Mutex
+		routes map[string]handler
+		nextID int
+
+		jobs     map[int]*models.JobDetails
+		failures []jobFailure
+		pageSize int
+
+		servers        map[string]*server
+		firewalls      map[string][]string
+		securityGroups map[string]*securityGroup
+		zones          map[string]*zone
+		sshKeys        map[string]*models.SSHKey
+		stacks         map[string]*models.Stack
+		environments   map[string][]models.EnvironmentVariable
+		databases      map[string]*models.Database
+		databaseUsers  map[string]*models.DatabaseUser
+		sshUsers       map[string]*models.User
+	}
+
+	// handler handles a request to an endpoint, what it returns is sent back as the return of the response.
+	// It is called with the lock held.
+	handler func(r request) (any, error)
+
+	// request is a request to the API, with the query and form values merged.
+	request struct {
+		url.Values
+	}
+
+	// apiError is an error response from the API.
+	apiError struct {
+		status  int
+		message string
+	}
+
+	// jobFailure is how the next job to be created fails.
+	jobFailure struct {
+		message string
+		logs    []string
+	}
+)
+
+// NewServer starts a fake SiteHost API that accepts the client ID and API key.
+func NewServer(clientID string, apiKey string) *Server {
+	s := &Server{
+		clientID:       clientID,
+		apiKey:         apiKey,
+		jobs:           map[int]*models.JobDetails{},
+		servers:        map[string]*server{},
+		firewalls:      map[string][]string{},
+		securityGroups: map[string]*securityGroup{},
+		zones:          map[string]*zone{},
+		sshKeys:        map[string]*models.SSHKey{},
+		stacks:         map[string]*models.Stack{},
+		environments:   map[string][]models.EnvironmentVariable{},
+		databases:      map[string]*models.Database{},
+		databaseUsers:  map[string]*models.DatabaseUser{},
+		sshUsers:       map[string]*models.User{},
+	}
+
+	s.routes = map[string]handler{
+		"api/get_info.json": s.getInfo,
+		"job/get.json":      s.getJob,
+	}
+	s.addServerRoutes()
+	s.addFirewallRoutes()
+	s.addDNSRoutes()
+	s.addSSHKeyRoutes()
+	s.addStackRoutes()
+	s.addDatabaseRoutes()
+	s.addSSHUserRoutes()
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Endpoint returns the address to use for the api_endpoint provider setting.
+func (s *Server) Endpoint() string {
+	return s.URL + "/" + apiVersion + "/"
+}
+
+// SetPageSize sets how many items the list endpoints return on a page, when the request doesn't say.
+func (s *Server) SetPageSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pageSize = size
+}
+
+// FailNextJob makes the next job that is created fail, the log lines come first and the message is the last, error, line.
+func (s *Server) FailNextJob(message string, logs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, jobFailure{message: message, logs: logs})
+}
+
+// Error returns the message of the error response.
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// notFound returns a not found error response, like the API does when the thing asked for does not exist.
+func notFound(format string, a ...any) error {
+	return &apiError{status: http.StatusNotFound, message: fmt.Sprintf(format, a...)}
+}
+
+// invalid returns an error response for a request the API would reject.
+func invalid(format string, a ...any) error {
+	return &apiError{status: http.StatusBadRequest, message: fmt.Sprintf(format, a...)}
+}
+
+// serveHTTP authenticates the request and passes it to the handler for the endpoint.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeResponse(w, http.StatusBadRequest, false, err.Error(), nil)
+		return
+	}
+
+	// the path starts with the api version, which the fake doesn't care about.
+	_, endpoint, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+	if r.Form.Get("apikey") != s.apiKey || r.Form.Get("client_id") != s.clientID {
+		writeResponse(w, http.StatusForbidden, false, "Invalid API key or client ID", nil)
+		return
+	}
+
+	h, ok := s.routes[endpoint]
+	if !ok {
+		writeResponse(w, http.StatusNotFound, false, "Unknown endpoint "+endpoint, nil)
+		return
+	}
+
+	s.mu.Lock()
+	ret, err := h(request{r.Form})
+	s.mu.Unlock()
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		if e, ok := err.(*apiError); ok {
+			status = e.status
+		}
+
+		writeResponse(w, status, false, err.Error(), nil)
+		return
+	}
+
+	writeResponse(w, http.StatusOK, true, "Successful", ret)
+}
+
+// writeResponse writes the response in the envelope the API uses.
+func writeResponse(w http.ResponseWriter, status int, ok bool, message string, ret any) {
+	body, err := json.Marshal(map[string]any{
+		"status": ok,
+		"msg":    message,
+		"return": ret,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// getInfo returns the details of the API key.
+func (s *Server) getInfo(_ request) (any, error) {
+	return map[string]any{
+		"client_id":  s.clientID,
+		"contact_id": "1",
+		"modules":    []string{"server", "dns", "cloud", "ssh"},
+		"roles":      []string{"admin"},
+	}, nil
+}
+
+// newJob creates a job, which has completed, unless FailNextJob asked for it to fail.
+func (s *Server) newJob() models.Job {
+	id := s.newID()
+	now := timestamp()
+
+	details := &models.JobDetails{
+		State:     "Completed",
+		Created:   now,
+		Started:   now,
+		Completed: now,
+		Logs:      []models.Log{{Date: now, Level: "info", Message: "Job completed"}},
+	}
+
+	if len(s.failures) > 0 {
+		failure := s.failures[0]
+		s.failures = s.failures[1:]
+
+		details.State = "Failed"
+		details.Logs = []models.Log{}
+		for _, l := range failure.logs {
+			details.Logs = append(details.Logs, models.Log{Date: now, Level: "info", Message: l})
+		}
+		details.Logs = append(details.Logs, models.Log{Date: now, Level: "error", Message: failure.message})
+	}
+
+	s.jobs[id] = details
+
+	return models.Job{ID: id, Type: "daemon"}
+}
+
+// jobReturn is the return of the endpoints that start a job.
+func jobReturn(job models.Job) map[string]any {
+	return map[string]any{"job": job}
+}
+
+// getJob returns the details of a job.
+func (s *Server) getJob(r request) (any, error) {
+	id, err := strconv.Atoi(r.Get("id"))
+	if err != nil {
+		return nil, invalid("Invalid job id %s", r.Get("id"))
+	}
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, notFound("Job %d not found", id)
+	}
+
+	return job, nil
+}
+
+// newID returns the next ID, IDs are shared by everything so they are unique.
+func (s *Server) newID() int {
+	s.nextID++
+	return s.nextID
+}
+
+// timestamp returns the current time in the format the API uses.
+func timestamp() string {
+	return time.Now().UTC().Format(dateFormat)
+}
+
+// page returns the page of the list endpoints the request asks for, with filters[page_number] and filters[page_size].
+// The page size defaults to the one set with SetPageSize, or everything on the one page.
+func page[T any](r request, pageSize int, data []T) map[string]any {
+	if data == nil {
+		data = []T{}
+	}
+
+	if size, err := strconv.Atoi(r.Get("filters[page_size]")); err == nil && size > 0 {
+		pageSize = size
+	}
+
+	if pageSize <= 0 {
+		pageSize = max(len(data), 1)
+	}
+
+	number, err := strconv.Atoi(r.Get("filters[page_number]"))
+	if err != nil || number < 1 {
+		number = 1
+	}
+
+	total := len(data)
+	start := min((number-1)*pageSize, total)
+	data = data[start:min(start+pageSize, total)]
+
+	return map[string]any{
+		"data":          data,
+		"total_items":   total,
+		"current_items": len(data),
+		"current_page":  number,
+		"total_pages":   max((total+pageSize-1)/pageSize, 1),
+	}
+}
+
+// required returns the value of a parameter, or an error if it isn't there.
+func (r request) required(key string) (string, error) {
+	v := r.Get(key)
+	if v == "" {
+		return "", invalid("Missing parameter %s", key)
+	}
+
+	return v, nil
+}
+
+// indexed returns the values of parameters like key[0], key[1], in order of the index.
+func (r request) indexed(key string) []string {
+	indexes := r.indexes(key, "")
+
+	values := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		values = append(values, r.Get(fmt.Sprintf("%s[%d]", key, i)))
+	}
+
+	return values
+}
+
+// indexes returns the indexes used in parameters like key[0]suffix, key[1]suffix, in order.
+func (r request) indexes(key string, suffix string) []int {
+	var indexes []int
+	for k := range r.Values {
+		rest, ok := strings.CutPrefix(k, key+"[")
+		if !ok {
+			continue
+		}
+
+		index, rest, ok := strings.Cut(rest, "]")
+		if !ok || !strings.HasPrefix(rest, suffix) {
+			continue
+		}
+
+		i, err := strconv.Atoi(index)
+		if err != nil || containsInt(indexes, i) {
+			continue
+		}
+
+		indexes = append(indexes, i)
+	}
+
+	sort.Ints(indexes)
+
+	return indexes
+}
+
+// containsInt checks if the int is in the list.
+func containsInt(list []int, v int) bool {
+	for _, i := range list {
+		if i == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// boolValue parses the 0 and 1 the API uses for booleans.
+func boolValue(v string) bool {
+	return v == "1" || strings.EqualFold(v, "true")
+}
+
+// sortedKeys returns the keys of the map, sorted.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,422 @@
+package mockapi
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/gosh/pkg/shtypes"
+)
+
+type (
+	// server is a server, with the changes that are staged until they are committed.
+	server struct {
+		models.Server
+
+		stagedPlan  string
+		stagedDisks map[string]int
+	}
+
+	// location is a location servers can be deployed to.
+	location struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+
+	// product is a plan servers can be deployed with, the RAM is in MB and the disk in GB.
+	product struct {
+		Code  string `json:"code"`
+		Name  string `json:"name"`
+		Type  string `json:"type"`
+		Cores int    `json:"cores"`
+		RAM   int    `json:"ram"`
+		Disk  int    `json:"disk"`
+		Price string `json:"price"`
+	}
+
+	// image is an image servers can be deployed from.
+	image struct {
+		Code  string `json:"code"`
+		Label string `json:"label"`
+	}
+)
+
+var (
+	// locations are the locations the fake knows about.
+	locations = []location{
+		{Code: "AKLCITY", Name: "Auckland City"},
+		{Code: "CHCCITY", Name: "Christchurch City"},
+	}
+
+	// products are the products the fake knows about.
+	products = []product{
+		{Code: "XENLIT", Name: "VPS Lite", Type: "VPS", Cores: 1, RAM: 1024, Disk: 30, Price: "20.00"},
+		{Code: "XENPRO", Name: "VPS Pro", Type: "VPS", Cores: 2, RAM: 4096, Disk: 80, Price: "60.00"},
+		{Code: "XENMAX", Name: "VPS Max", Type: "VPS", Cores: 4, RAM: 8192, Disk: 160, Price: "120.00"},
+	}
+
+	// images are the images the fake knows about.
+	images = []image{
+		{Code: "ubuntu-jammy-pvh.amd64", Label: "Ubuntu 22.04 LTS"},
+		{Code: "ubuntu-noble-pvh.amd64", Label: "Ubuntu 24.04 LTS"},
+		{Code: "debian-bookworm-pvh.amd64", Label: "Debian 12"},
+	}
+)
+
+// addServerRoutes adds the server endpoints.
+func (s *Server) addServerRoutes() {
+	s.routes["server/provision.json"] = s.provisionServer
+	s.routes["server/get_server.json"] = s.getServer
+	s.routes["server/list_servers.json"] = s.listServers
+	s.routes["server/update.json"] = s.updateServer
+	s.routes["server/upgrade_plan.json"] = s.upgradePlan
+	s.routes["server/add_disk.json"] = s.addDisk
+	s.routes["server/resize_disk.json"] = s.resizeDisk
+	s.routes["server/commit_disk_changes.json"] = s.commitDiskChanges
+	s.routes["server/change_state.json"] = s.changeState
+	s.routes["server/reinstall.json"] = s.reinstallServer
+	s.routes["server/delete.json"] = s.deleteServer
+	s.routes["server/list_locations.json"] = func(request) (any, error) { return locations, nil }
+	s.routes["server/list_products.json"] = func(request) (any, error) { return products, nil }
+	s.routes["server/list_images.json"] = func(request) (any, error) { return images, nil }
+}
+
+// findServer returns the server with the name.
+func (s *Server) findServer(name string) (*server, error) {
+	srv, ok := s.servers[name]
+	if !ok {
+		return nil, notFound("Server %s not found", name)
+	}
+
+	return srv, nil
+}
+
+// provisionServer creates a server, with a root disk the size of the plan and a swap disk.
+func (s *Server) provisionServer(r request) (any, error) {
+	label, err := r.required("label")
+	if err != nil {
+		return nil, err
+	}
+
+	loc, ok := findLocation(r.Get("location"))
+	if !ok {
+		return nil, invalid("Invalid location %s", r.Get("location"))
+	}
+
+	prod, ok := findProduct(r.Get("product_code"))
+	if !ok {
+		return nil, invalid("Invalid product code %s", r.Get("product_code"))
+	}
+
+	img, ok := findImage(r.Get("image"))
+	if !ok {
+		return nil, invalid("Invalid image %s", r.Get("image"))
+	}
+
+	for _, key := range r.Values["params[ssh_keys][]"] {
+		if _, ok := s.sshKeys[key]; !ok {
+			return nil, invalid("SSH key %s not found", key)
+		}
+	}
+
+	id := s.newID()
+	name := fmt.Sprintf("mock%d", id)
+
+	srv := &server{Server: models.Server{
+		Name:         name,
+		Label:        label,
+		ClientID:     shtypes.MaybeString(s.clientID),
+		Created:      timestamp(),
+		State:        "On",
+		Os:           img.Label,
+		Distro:       img.Code,
+		LocationCode: loc.Code,
+		LocationName: loc.Name,
+		Interfaces:   []string{"eth0"},
+		Ips: []models.IP{
+			{ID: strconv.Itoa(s.newID()), IPAddr: fmt.Sprintf("192.0.2.%d", id%250+1), AddrFamily: 4, Primary: true, IPType: "public"},
+			{ID: strconv.Itoa(s.newID()), IPAddr: fmt.Sprintf("2001:db8::%x", id), AddrFamily: 6, IPType: "public"},
+		},
+		Partitions: []models.Partition{
+			{ID: strconv.Itoa(s.newID()), Name: "root", Device: "xvda1", Mountpoint: "/", Fstype: "ext4", Type: "disk"},
+			{ID: strconv.Itoa(s.newID()), Name: "swap", Device: "xvda2", Fstype: "swap", Type: "swap", Size: "1"},
+		},
+	}}
+	setProduct(srv, prod)
+
+	s.servers[name] = srv
+
+	ips := make([]string, 0, len(srv.Ips))
+	for _, ip := range srv.Ips {
+		ips = append(ips, ip.IPAddr)
+	}
+
+	return map[string]any{
+		"job":       s.newJob(),
+		"name":      name,
+		"password":  fmt.Sprintf("mock-password-%d", id),
+		"ips":       ips,
+		"server_id": strconv.Itoa(id),
+	}, nil
+}
+
+// getServer returns a server.
+func (s *Server) getServer(r request) (any, error) {
+	srv, err := s.findServer(r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	return srv.Server, nil
+}
+
+// listServers returns all the servers, sorted by name.
+func (s *Server) listServers(r request) (any, error) {
+	names := sortedKeys(s.servers)
+
+	servers := make([]models.Server, 0, len(names))
+	for _, name := range names {
+		servers = append(servers, s.servers[name].Server)
+	}
+
+	return page(r, s.pageSize, servers), nil
+}
+
+// updateServer changes the label of a server.
+func (s *Server) updateServer(r request) (any, error) {
+	srv, err := s.findServer(r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	if label := r.Get("updates[label]"); label != "" {
+		srv.Label = label
+	}
+
+	return nil, nil
+}
+
+// upgradePlan stages a change of plan, servers can only be upgraded.
+func (s *Server) upgradePlan(r request) (any, error) {
+	srv, err := s.findServer(r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	prod, ok := findProduct(r.Get("plan"))
+	if !ok {
+		return nil, invalid("Invalid plan %s", r.Get("plan"))
+	}
+
+	current, _ := findProduct(srv.ProductCode)
+	if prod.Cores < current.Cores || prod.RAM < current.RAM || prod.Disk < current.Disk {
+		return nil, invalid("Server %s can not be downgraded to %s", srv.Name, prod.Code)
+	}
+
+	srv.stagedPlan = prod.Code
+
+	return nil, nil
+}
+
+// addDisk stages a new disk.
+func (s *Server) addDisk(r request) (any, error) {
+	return nil, s.stageDisk(r, false)
+}
+
+// resizeDisk stages growing a disk.
+func (s *Server) resizeDisk(r request) (any, error) {
+	return nil, s.stageDisk(r, true)
+}
+
+// stageDisk stages adding or growing a disk, the disk has to exist to be resized, and not exist to be added.
+func (s *Server) stageDisk(r request, exists bool) error {
+	srv, err := s.findServer(r.Get("name"))
+	if err != nil {
+		return err
+	}
+
+	label, err := r.required("label")
+	if err != nil {
+		return err
+	}
+
+	size, err := strconv.Atoi(r.Get("size"))
+	if err != nil || size <= 0 {
+		return invalid("Invalid size %s", r.Get("size"))
+	}
+
+	partition := srv.partition(label)
+	switch {
+	case exists && partition == nil:
+		return notFound("Disk %s not found", label)
+	case !exists && partition != nil:
+		return invalid("Disk %s already exists", label)
+	case partition != nil && size < atoi(partition.Size):
+		return invalid("Disk %s can not be shrunk", label)
+	}
+
+	if srv.stagedDisks == nil {
+		srv.stagedDisks = map[string]int{}
+	}
+	srv.stagedDisks[label] = size
+
+	return nil
+}
+
+// commitDiskChanges applies the staged plan and disk changes.
+func (s *Server) commitDiskChanges(r request) (any, error) {
+	srv, err := s.findServer(r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	if srv.stagedPlan != "" {
+		prod, _ := findProduct(srv.stagedPlan)
+		setProduct(srv, prod)
+		srv.stagedPlan = ""
+	}
+
+	for _, label := range sortedKeys(srv.stagedDisks) {
+		size := strconv.Itoa(srv.stagedDisks[label])
+
+		if partition := srv.partition(label); partition != nil {
+			partition.Size = size
+			continue
+		}
+
+		srv.Partitions = append(srv.Partitions, models.Partition{
+			ID:         strconv.Itoa(s.newID()),
+			Name:       label,
+			Device:     fmt.Sprintf("xvd%c", 'a'+len(srv.Partitions)),
+			Mountpoint: "/mnt/" + label,
+			Fstype:     "ext4",
+			Type:       "disk",
+			Size:       size,
+		})
+	}
+	srv.stagedDisks = nil
+
+	return jobReturn(s.newJob()), nil
+}
+
+// changeState powers a server on or off, or reboots it.
+func (s *Server) changeState(r request) (any, error) {
+	srv, err := s.findServer(r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	switch r.Get("state") {
+	case "power_on", "reboot":
+		srv.State = "On"
+	case "power_off":
+		srv.State = "Off"
+	default:
+		return nil, invalid("Invalid state %s", r.Get("state"))
+	}
+
+	return jobReturn(s.newJob()), nil
+}
+
+// reinstallServer reinstalls a server from an image, it gets a new root password.
+func (s *Server) reinstallServer(r request) (any, error) {
+	srv, err := s.findServer(r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	img, ok := findImage(r.Get("image"))
+	if !ok {
+		return nil, invalid("Invalid image %s", r.Get("image"))
+	}
+
+	srv.Os = img.Label
+	srv.Distro = img.Code
+	srv.State = "On"
+
+	return map[string]any{
+		"job":      s.newJob(),
+		"password": fmt.Sprintf("mock-password-%d", s.newID()),
+	}, nil
+}
+
+// deleteServer deletes a server, along with its firewall.
+func (s *Server) deleteServer(r request) (any, error) {
+	srv, err := s.findServer(r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	delete(s.servers, srv.Name)
+	delete(s.firewalls, srv.Name)
+
+	return jobReturn(s.newJob()), nil
+}
+
+// partition returns the partition with the name, or nil if there isn't one.
+func (srv *server) partition(name string) *models.Partition {
+	for i := range srv.Partitions {
+		if srv.Partitions[i].Name == name {
+			return &srv.Partitions[i]
+		}
+	}
+
+	return nil
+}
+
+// setProduct puts a server on a product, growing the root disk to the size of the plan.
+func setProduct(srv *server, prod product) {
+	srv.ProductCode = prod.Code
+	srv.ProductName = prod.Name
+	srv.ProductType = prod.Type
+	srv.Cores = shtypes.MaybeBigInt(prod.Cores)
+	srv.RAM = strconv.Itoa(prod.RAM)
+	srv.Disk = shtypes.MaybeBigInt(prod.Disk)
+
+	if root := srv.partition("root"); root != nil && atoi(root.Size) < prod.Disk {
+		root.Size = strconv.Itoa(prod.Disk)
+	}
+}
+
+// findLocation returns the location with the code.
+func findLocation(code string) (location, bool) {
+	for _, l := range locations {
+		if l.Code == code {
+			return l, true
+		}
+	}
+
+	return location{}, false
+}
+
+// findProduct returns the product with the code.
+func findProduct(code string) (product, bool) {
+	for _, p := range products {
+		if p.Code == code {
+			return p, true
+		}
+	}
+
+	return product{}, false
+}
+
+// findImage returns the image with the code.
+func findImage(code string) (image, bool) {
+	for _, i := range images {
+		if i.Code == code {
+			return i, true
+		}
+	}
+
+	return image{}, false
+}
+
+// atoi converts a string to an int, anything that isn't a number is 0.
+func atoi(v string) int {
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0
+	}
+
+	return i
+}
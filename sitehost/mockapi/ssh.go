@@ -0,0 +1,97 @@
+package mockapi
+
+import (
+	"strconv"
+
+	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/gosh/pkg/shtypes"
+)
+
+// addSSHKeyRoutes adds the SSH key endpoints.
+func (s *Server) addSSHKeyRoutes() {
+	s.routes["ssh/key/add.json"] = s.addSSHKey
+	s.routes["ssh/key/get.json"] = s.getSSHKey
+	s.routes["ssh/key/list_all.json"] = s.listSSHKeys
+	s.routes["ssh/key/update.json"] = s.updateSSHKey
+	s.routes["ssh/key/remove.json"] = s.removeSSHKey
+}
+
+// findSSHKey returns the SSH key with the ID.
+func (s *Server) findSSHKey(id string) (*models.SSHKey, error) {
+	key, ok := s.sshKeys[id]
+	if !ok {
+		return nil, notFound("SSH key %s not found", id)
+	}
+
+	return key, nil
+}
+
+// addSSHKey adds an SSH key to the account.
+func (s *Server) addSSHKey(r request) (any, error) {
+	label, err := r.required("label")
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := r.required("content")
+	if err != nil {
+		return nil, err
+	}
+
+	id := strconv.Itoa(s.newID())
+	now := timestamp()
+
+	s.sshKeys[id] = &models.SSHKey{
+		ID:                id,
+		ClientID:          s.clientID,
+		Label:             label,
+		Content:           content,
+		DateAdded:         now,
+		DateUpdated:       now,
+		CustomImageAccess: shtypes.MaybeBool(boolValue(r.Get("params[custom_image_access]"))),
+	}
+
+	return map[string]string{"key_id": id}, nil
+}
+
+// getSSHKey returns an SSH key.
+func (s *Server) getSSHKey(r request) (any, error) {
+	return s.findSSHKey(r.Get("key_id"))
+}
+
+// listSSHKeys returns the SSH keys, sorted by ID.
+func (s *Server) listSSHKeys(r request) (any, error) {
+	keys := make([]models.SSHKey, 0, len(s.sshKeys))
+	for _, id := range sortedKeys(s.sshKeys) {
+		keys = append(keys, *s.sshKeys[id])
+	}
+
+	return page(r, s.pageSize, keys), nil
+}
+
+// updateSSHKey changes an SSH key.
+func (s *Server) updateSSHKey(r request) (any, error) {
+	key, err := s.findSSHKey(r.Get("key_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	key.Label = valueOr(r.Get("params[label]"), key.Label)
+	key.Content = valueOr(r.Get("params[content]"), key.Content)
+	key.CustomImageAccess = shtypes.MaybeBool(boolValue(r.Get("params[custom_image_access]")))
+	key.DateUpdated = timestamp()
+
+	return nil, nil
+}
+
+// removeSSHKey removes an SSH key from the account.
+func (s *Server) removeSSHKey(r request) (any, error) {
+	key, err := s.findSSHKey(r.Get("key_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	delete(s.sshKeys, key.ID)
+
+	return nil, nil
+}
@@ -0,0 +1,137 @@
+package mockapi
+
+import (
+	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/gosh/pkg/shtypes"
+)
+
+// addSSHUserRoutes adds the cloud SSH user endpoints.
+func (s *Server) addSSHUserRoutes() {
+	s.routes["cloud/ssh/user/add.json"] = s.addSSHUser
+	s.routes["cloud/ssh/user/get.json"] = s.getSSHUser
+	s.routes["cloud/ssh/user/list_all.json"] = s.listSSHUsers
+	s.routes["cloud/ssh/user/update.json"] = s.updateSSHUser
+	s.routes["cloud/ssh/user/delete.json"] = s.deleteSSHUser
+}
+
+// sshUserKey is the key of an SSH user, usernames are unique to a server.
+func sshUserKey(server, username string) string {
+	return server + "/" + username
+}
+
+// findSSHUser returns the SSH user the request is for.
+func (s *Server) findSSHUser(r request) (*models.User, error) {
+	user, ok := s.sshUsers[sshUserKey(r.Get("server_name"), r.Get("username"))]
+	if !ok {
+		return nil, notFound("SSH user %s not found", r.Get("username"))
+	}
+
+	return user, nil
+}
+
+// addSSHUser creates an SSH user on a server.
+func (s *Server) addSSHUser(r request) (any, error) {
+	srv, err := s.findServer(r.Get("server_name"))
+	if err != nil {
+		return nil, err
+	}
+
+	username, err := r.required("username")
+	if err != nil {
+		return nil, err
+	}
+
+	key := sshUserKey(srv.Name, username)
+	if _, ok := s.sshUsers[key]; ok {
+		return nil, invalid("SSH user %s already exists", username)
+	}
+
+	now := timestamp()
+	user := &models.User{
+		Username:    username,
+		ServerID:    srv.Name,
+		ClientID:    s.clientID,
+		HomeDir:     "/home/" + username,
+		DateAdded:   now,
+		DateUpdated: now,
+		ServerName:  srv.Name,
+		ServerLabel: srv.Label,
+		ServerOwner: true,
+		IPAddr:      srv.Ips[0].IPAddr,
+	}
+
+	if err := s.setSSHUserParams(user, r); err != nil {
+		return nil, err
+	}
+
+	s.sshUsers[key] = user
+
+	return jobReturn(s.newJob()), nil
+}
+
+// getSSHUser returns an SSH user.
+func (s *Server) getSSHUser(r request) (any, error) {
+	return s.findSSHUser(r)
+}
+
+// listSSHUsers returns the SSH users, filtered by server and username.
+func (s *Server) listSSHUsers(r request) (any, error) {
+	users := make([]models.User, 0, len(s.sshUsers))
+	for _, key := range sortedKeys(s.sshUsers) {
+		user := s.sshUsers[key]
+		if matches(r, "filters[server_name]", user.ServerName) && matches(r, "filters[username]", user.Username) {
+			users = append(users, *user)
+		}
+	}
+
+	return page(r, s.pageSize, users), nil
+}
+
+// updateSSHUser replaces the containers, volumes, SSH keys and settings of an SSH user.
+func (s *Server) updateSSHUser(r request) (any, error) {
+	user, err := s.findSSHUser(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.setSSHUserParams(user, r); err != nil {
+		return nil, err
+	}
+
+	user.DateUpdated = timestamp()
+
+	return jobReturn(s.newJob()), nil
+}
+
+// deleteSSHUser deletes an SSH user.
+func (s *Server) deleteSSHUser(r request) (any, error) {
+	user, err := s.findSSHUser(r)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(s.sshUsers, sshUserKey(user.ServerName, user.Username))
+
+	return jobReturn(s.newJob()), nil
+}
+
+// setSSHUserParams sets the containers, volumes, SSH keys and settings of an SSH user from the params of the request.
+// The SSH keys are given by ID, and have to be keys on the account.
+func (s *Server) setSSHUserParams(user *models.User, r request) error {
+	keys := make([]models.SSHKey, 0, len(r.Values["params[ssh_keys][]"]))
+	for _, id := range r.Values["params[ssh_keys][]"] {
+		key, err := s.findSSHKey(id)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, *key)
+	}
+
+	user.SSHKeys = keys
+	user.Containers = append([]string{}, r.Values["params[containers][]"]...)
+	user.Volumes = append([]string{}, r.Values["params[volumes][]"]...)
+	user.ReadOnlyConfig = shtypes.MaybeBool(boolValue(r.Get("params[read_only_config]")))
+
+	return nil
+}
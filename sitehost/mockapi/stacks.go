@@ -0,0 +1,274 @@
+package mockapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sitehostnz/gosh/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// addStackRoutes adds the stack and stack environment endpoints.
+func (s *Server) addStackRoutes() {
+	s.routes["cloud/stack/generate_name.json"] = s.generateStackName
+	s.routes["cloud/stack/add.json"] = s.addStack
+	s.routes["cloud/stack/get.json"] = s.getStack
+	s.routes["cloud/stack/list_all.json"] = s.listStacks
+	s.routes["cloud/stack/update.json"] = s.updateStack
+	s.routes["cloud/stack/delete.json"] = s.deleteStack
+	s.routes["cloud/stack/start.json"] = s.stackState("running")
+	s.routes["cloud/stack/stop.json"] = s.stackState("stopped")
+	s.routes["cloud/stack/restart.json"] = s.stackState("running")
+	s.routes["cloud/stack/environment/get.json"] = s.getEnvironment
+	s.routes["cloud/stack/environment/update.json"] = s.updateEnvironment
+}
+
+// stackKey is the key of a stack, stack names are unique to a server.
+func stackKey(server, name string) string {
+	return server + "/" + name
+}
+
+// environmentKey is the key of the environment of a service in a stack.
+func environmentKey(server, project, service string) string {
+	return server + "/" + project + "/" + service
+}
+
+// findStack returns the stack on the server with the name.
+func (s *Server) findStack(server, name string) (*models.Stack, error) {
+	stack, ok := s.stacks[stackKey(server, name)]
+	if !ok {
+		return nil, notFound("Stack %s not found on server %s", name, server)
+	}
+
+	return stack, nil
+}
+
+// generateStackName returns a name for a new stack.
+func (s *Server) generateStackName(_ request) (any, error) {
+	return map[string]string{"name": fmt.Sprintf("mockstack%d", s.newID())}, nil
+}
+
+// addStack creates a stack on a server, with its containers taken from the docker compose file.
+func (s *Server) addStack(r request) (any, error) {
+	srv, err := s.findServer(r.Get("server"))
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.stacks[stackKey(srv.Name, name)]; ok {
+		return nil, invalid("Stack %s already exists on server %s", name, srv.Name)
+	}
+
+	dockerCompose := r.Get("docker_compose")
+
+	containers, err := composeContainers(dockerCompose)
+	if err != nil {
+		return nil, err
+	}
+
+	now := timestamp()
+	s.stacks[stackKey(srv.Name, name)] = &models.Stack{
+		ClientID:    s.clientID,
+		ServerID:    srv.Name,
+		Server:      srv.Name,
+		ServerLabel: srv.Label,
+		Name:        name,
+		Label:       r.Get("label"),
+		DockerFile:  dockerCompose,
+		IPAddress:   srv.Ips[0].IPAddr,
+		DateAdded:   now,
+		DateUpdated: now,
+		Containers:  containers,
+		ServerOwner: true,
+	}
+
+	if env := r.Get("environments[" + name + ".env]"); env != "" {
+		variables, err := parseEnvironment(env)
+		if err != nil {
+			return nil, err
+		}
+
+		s.environments[environmentKey(srv.Name, name, name)] = variables
+	}
+
+	return jobReturn(s.newJob()), nil
+}
+
+// getStack returns a stack.
+func (s *Server) getStack(r request) (any, error) {
+	return s.findStack(r.Get("server"), r.Get("name"))
+}
+
+// listStacks returns the stacks, filtered by server.
+func (s *Server) listStacks(r request) (any, error) {
+	server := r.Get("filters[server_name]")
+
+	stacks := make([]models.Stack, 0, len(s.stacks))
+	for _, key := range sortedKeys(s.stacks) {
+		stack := s.stacks[key]
+		if server == "" || stack.Server == server {
+			stacks = append(stacks, *stack)
+		}
+	}
+
+	return page(r, s.pageSize, stacks), nil
+}
+
+// updateStack changes the label and docker compose file of a stack.
+func (s *Server) updateStack(r request) (any, error) {
+	stack, err := s.findStack(r.Get("server"), r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	if dockerCompose := r.Get("params[docker_compose]"); dockerCompose != "" {
+		containers, err := composeContainers(dockerCompose)
+		if err != nil {
+			return nil, err
+		}
+
+		stack.DockerFile = dockerCompose
+		stack.Containers = containers
+	}
+
+	stack.Label = valueOr(r.Get("params[label]"), stack.Label)
+	stack.DateUpdated = timestamp()
+
+	return jobReturn(s.newJob()), nil
+}
+
+// deleteStack deletes a stack, along with its environments.
+func (s *Server) deleteStack(r request) (any, error) {
+	stack, err := s.findStack(r.Get("server"), r.Get("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	delete(s.stacks, stackKey(stack.Server, stack.Name))
+
+	prefix := stackKey(stack.Server, stack.Name) + "/"
+	for key := range s.environments {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.environments, key)
+		}
+	}
+
+	return jobReturn(s.newJob()), nil
+}
+
+// stackState returns a handler that changes the state of the containers of a stack.
+func (s *Server) stackState(state string) handler {
+	return func(r request) (any, error) {
+		stack, err := s.findStack(r.Get("server"), r.Get("name"))
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range stack.Containers {
+			stack.Containers[i].State = state
+		}
+
+		return jobReturn(s.newJob()), nil
+	}
+}
+
+// getEnvironment returns the environment variables of a service in a stack.
+func (s *Server) getEnvironment(r request) (any, error) {
+	stack, err := s.findStack(r.Get("server"), r.Get("project"))
+	if err != nil {
+		return nil, err
+	}
+
+	variables := s.environments[environmentKey(stack.Server, stack.Name, valueOr(r.Get("service"), stack.Name))]
+	if variables == nil {
+		variables = []models.EnvironmentVariable{}
+	}
+
+	return variables, nil
+}
+
+// updateEnvironment sets the environment variables of a service in a stack, a variable without content is removed.
+func (s *Server) updateEnvironment(r request) (any, error) {
+	stack, err := s.findStack(r.Get("server"), r.Get("project"))
+	if err != nil {
+		return nil, err
+	}
+
+	key := environmentKey(stack.Server, stack.Name, valueOr(r.Get("service"), stack.Name))
+
+	settings := map[string]string{}
+	for _, v := range s.environments[key] {
+		settings[v.Name] = v.Content
+	}
+
+	for _, i := range r.indexes("variables", "[name]") {
+		name := r.Get(fmt.Sprintf("variables[%d][name]", i))
+		content := r.Get(fmt.Sprintf("variables[%d][content]", i))
+
+		if content == "" {
+			delete(settings, name)
+			continue
+		}
+
+		settings[name] = content
+	}
+
+	s.environments[key] = environmentVariables(settings)
+
+	return jobReturn(s.newJob()), nil
+}
+
+// composeContainers returns a container for each service in the docker compose file.
+func composeContainers(dockerCompose string) ([]models.Container, error) {
+	var compose struct {
+		Services map[string]any `yaml:"services"`
+	}
+
+	if err := yaml.Unmarshal([]byte(dockerCompose), &compose); err != nil {
+		return nil, invalid("Invalid docker compose file: %s", err)
+	}
+
+	now := timestamp()
+
+	containers := make([]models.Container, 0, len(compose.Services))
+	for _, name := range sortedKeys(compose.Services) {
+		containers = append(containers, models.Container{
+			Name:        name,
+			ContainerID: name,
+			State:       "running",
+			DateCreated: now,
+		})
+	}
+
+	return containers, nil
+}
+
+// parseEnvironment parses the environment file sent when a stack is created.
+func parseEnvironment(env string) ([]models.EnvironmentVariable, error) {
+	var file struct {
+		Vars map[string]string `yaml:"vars"`
+	}
+
+	if err := yaml.Unmarshal([]byte(env), &file); err != nil {
+		return nil, invalid("Invalid environment: %s", err)
+	}
+
+	return environmentVariables(file.Vars), nil
+}
+
+// environmentVariables converts the settings into environment variables, sorted by name.
+func environmentVariables(settings map[string]string) []models.EnvironmentVariable {
+	names := sortedKeys(settings)
+
+	variables := make([]models.EnvironmentVariable, 0, len(names))
+	for _, name := range names {
+		variables = append(variables, models.EnvironmentVariable{Name: name, Content: settings[name]})
+	}
+
+	return variables
+}
@@ -0,0 +1,25 @@
+package sitehost
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/framework"
+)
+
+// ProviderServer returns the SDKv2 and framework providers served together through a mux server.
+func ProviderServer(ctx context.Context, version string) (func() tfprotov5.ProviderServer, error) {
+	providers := []func() tfprotov5.ProviderServer{
+		New(version)().GRPCProvider,
+		providerserver.NewProtocol5(framework.New(version)()),
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, providers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return muxServer.ProviderServer, nil
+}
@@ -4,6 +4,7 @@ package sitehost
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -27,21 +28,37 @@ func New(version string) func() *schema.Provider {
 	return func() *schema.Provider {
 		p := &schema.Provider{
 			Schema: map[string]*schema.Schema{
+				// these are optional, rather than required with a default, so the schema matches the framework provider.
 				"client_id": {
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("SH_CLIENT_ID", nil),
-					Description: "The client identifier that allows you access to your SiteHost account.",
+					Description: helper.ClientIDDescription,
 				}, "api_key": {
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("SH_APIKEY", nil),
-					Description: "The API Key that allows you access to your SiteHost account.",
+					Description: helper.APIKeyDescription,
 					Sensitive:   true,
 				}, "api_endpoint": {
 					Type:        schema.TypeString,
 					Optional:    true,
-					Description: "The HTTPS API address of the SiteHost API to use.",
+					Description: helper.APIEndpointDescription,
+				}, "max_retries": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Default:     helper.DefaultMaxRetries,
+					Description: helper.MaxRetriesDescription,
+				}, "retry_max_wait": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     helper.DefaultRetryMaxWait.String(),
+					Description: helper.RetryMaxWaitDescription,
+				}, "requests_per_second": {
+					Type:        schema.TypeFloat,
+					Optional:    true,
+					Default:     float64(helper.DefaultRequestsPerSecond),
+					Description: helper.RequestsPerSecondDescription,
 				},
 			},
 			DataSourcesMap: map[string]*schema.Resource{
@@ -53,7 +70,16 @@ func New(version string) func() *schema.Provider {
 
 				"sitehost_cloud_ssh_user": ssh_user.DataSource(),
 
-				"sitehost_server": server.DataSource(),
+				"sitehost_dns_zones":       dns.ZonesDataSource(),
+				"sitehost_dns_zone":        dns.ZoneDataSource(),
+				"sitehost_dns_records":     dns.RecordsDataSource(),
+				"sitehost_dns_zone_export": dns.ZoneExportDataSource(),
+
+				"sitehost_server":           server.DataSource(),
+				"sitehost_servers":          server.ListDataSource(),
+				"sitehost_server_locations": server.LocationsDataSource(),
+				"sitehost_server_products":  server.ProductsDataSource(),
+				"sitehost_server_images":    server.ImagesDataSource(),
 
 				"sitehost_stack":             stack.DataSource(),
 				"sitehost_stacks":            stack.ListDataSource(),
@@ -71,12 +97,14 @@ func New(version string) func() *schema.Provider {
 				"sitehost_cloud_database_grant": grant.Resource(),
 				"sitehost_cloud_ssh_user":       ssh_user.Resource(),
 
-				"sitehost_dns_zone":   dns.ZoneResource(),
-				"sitehost_dns_record": dns.RecordResource(),
+				"sitehost_dns_zone":      dns.ZoneResource(),
+				"sitehost_dns_record":    dns.RecordResource(),
+				"sitehost_dns_zone_file": dns.ZoneFileResource(),
 
 				"sitehost_ssh_key": sshkey.Resource(),
 
 				"sitehost_server":                server.Resource(),
+				"sitehost_server_power":          server.PowerResource(),
 				"sitehost_server_security_group": securitygroups.Resource(),
 				"sitehost_server_firewall":       firewall.Resource(),
 			},
@@ -91,13 +119,29 @@ func New(version string) func() *schema.Provider {
 }
 
 // configure returns the Config with connection data.
-func configure(_ context.Context, version string, d *schema.ResourceData) (any, diag.Diagnostics) {
+func configure(ctx context.Context, version string, d *schema.ResourceData) (any, diag.Diagnostics) {
+	retryMaxWait, err := time.ParseDuration(fmt.Sprint(d.Get("retry_max_wait")))
+	if err != nil {
+		return nil, diag.Errorf("retry_max_wait must be a duration, such as 30s: %s", err)
+	}
+
+	// these have defaults, so they are always set.
+	maxRetries, _ := d.Get("max_retries").(int)
+	requestsPerSecond, _ := d.Get("requests_per_second").(float64)
+
 	config := &helper.Config{
-		APIKey:           fmt.Sprint(d.Get("api_key")),
-		ClientID:         fmt.Sprint(d.Get("client_id")),
-		APIEndpoint:      fmt.Sprint(d.Get("api_endpoint")),
-		TerraformVersion: version,
+		APIKey:            fmt.Sprint(d.Get("api_key")),
+		ClientID:          fmt.Sprint(d.Get("client_id")),
+		APIEndpoint:       fmt.Sprint(d.Get("api_endpoint")),
+		TerraformVersion:  version,
+		MaxRetries:        maxRetries,
+		RetryMaxWait:      retryMaxWait,
+		RequestsPerSecond: requestsPerSecond,
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, diag.FromErr(err)
 	}
 
-	return config.Client()
+	return config.Client(ctx)
 }
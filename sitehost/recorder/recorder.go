@@ -0,0 +1,321 @@
+// Package recorder provides an HTTP transport that records SiteHost API traffic to a cassette file, and replays it.
+//
+// Recording a run against the real API, with the API key, client ID and passwords scrubbed from what is saved,
+// turns a bug report into a regression test that runs without a SiteHost account:
+//
+//	SH_CASSETTE=testdata/bug.yaml SH_CASSETTE_MODE=record terraform apply
+//	SH_CASSETTE=testdata/bug.yaml terraform apply
+//
+// When replaying, the requests have to be made in the order they were recorded, and the client ID,
+// API key and passwords can be anything, as they are scrubbed from the requests before they are compared.
+// The passwords in the responses, such as the root password of a new server, are replayed scrubbed.
+package recorder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// CassetteEnv is the environment variable with the path of the cassette to use.
+	CassetteEnv = "SH_CASSETTE"
+	// CassetteModeEnv is the environment variable with the mode to use the cassette in, record or replay.
+	CassetteModeEnv = "SH_CASSETTE_MODE"
+	// ScrubbedAPIKey replaces the API key in what is recorded.
+	ScrubbedAPIKey = "REDACTED"
+	// ScrubbedClientID replaces the client ID in what is recorded.
+	ScrubbedClientID = "0"
+	// ScrubbedPassword replaces passwords in what is recorded, such as the root password of a new server.
+	ScrubbedPassword = "REDACTED"
+)
+
+// passwordField matches a JSON field with password in its name, and a string value.
+var passwordField = regexp.MustCompile(`("[a-z_]*password[a-z_]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// The modes a cassette can be used in.
+const (
+	// ModeReplay answers requests from the cassette, without sending them.
+	ModeReplay Mode = "replay"
+	// ModeRecord sends requests on, and saves them and their responses to the cassette.
+	ModeRecord Mode = "record"
+)
+
+type (
+	// Mode is whether a Recorder records or replays.
+	Mode string
+
+	// Recorder is an http.RoundTripper that records to, or replays from, a cassette.
+	Recorder struct {
+		path      string
+		mode      Mode
+		transport http.RoundTripper
+
+		mu       sync.Mutex
+		cassette Cassette
+		next     int
+	}
+
+	// Cassette is the requests and responses that were recorded, in the order they were made.
+	Cassette struct {
+		Interactions []Interaction `yaml:"interactions"`
+	}
+
+	// Interaction is a request and the response to it.
+	Interaction struct {
+		Request  Request  `yaml:"request"`
+		Response Response `yaml:"response"`
+	}
+
+	// Request is a recorded request, the URL is the path and query, so the cassette doesn't depend on the endpoint.
+	Request struct {
+		Method string `yaml:"method"`
+		URL    string `yaml:"url"`
+		Body   string `yaml:"body,omitempty"`
+	}
+
+	// Response is a recorded response.
+	Response struct {
+		StatusCode  int    `yaml:"status_code"`
+		ContentType string `yaml:"content_type,omitempty"`
+		Body        string `yaml:"body"`
+	}
+)
+
+// New returns a Recorder for the cassette at the path.
+// In replay mode the cassette is loaded, in record mode it is written after every request, with requests sent on with the transport,
+// or http.DefaultTransport if that is nil.
+func New(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, transport: transport}
+	if r.transport == nil {
+		r.transport = http.DefaultTransport
+	}
+
+	switch mode {
+	case ModeRecord:
+		return r, nil
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading cassette: %w", err)
+		}
+
+		if err := yaml.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("error reading cassette %s: %w", path, err)
+		}
+
+		return r, nil
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q, must be %s or %s", mode, ModeRecord, ModeReplay)
+	}
+}
+
+// NewFromEnv returns a Recorder for the cassette in the SH_CASSETTE environment variable, in the mode in SH_CASSETTE_MODE,
+// which defaults to replay. It returns nil when SH_CASSETTE isn't set.
+func NewFromEnv() (*Recorder, error) {
+	path := os.Getenv(CassetteEnv)
+	if path == "" {
+		return nil, nil
+	}
+
+	mode := Mode(os.Getenv(CassetteModeEnv))
+	if mode == "" {
+		mode = ModeReplay
+	}
+
+	return New(path, mode, nil)
+}
+
+// RoundTrip records or replays the request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := scrubRequest(req, body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	return r.record(req, body, recorded)
+}
+
+// replay returns the response to the next request in the cassette, which has to be the same as the request.
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	if r.next >= len(r.cassette.Interactions) {
+		return nil, fmt.Errorf("cassette %s has no more interactions, got %s %s", r.path, recorded.Method, recorded.URL)
+	}
+
+	interaction := r.cassette.Interactions[r.next]
+	if interaction.Request != recorded {
+		return nil, fmt.Errorf(
+			"cassette %s interaction %d is %s %s %s, got %s %s %s",
+			r.path, r.next,
+			interaction.Request.Method, interaction.Request.URL, interaction.Request.Body,
+			recorded.Method, recorded.URL, recorded.Body,
+		)
+	}
+
+	r.next++
+
+	return newResponse(req, interaction.Response), nil
+}
+
+// record sends the request on, and saves it and the response to the cassette.
+func (r *Recorder) record(req *http.Request, body []byte, recorded Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	response := Response{
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        scrubResponse(req, string(data)),
+	}
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{Request: recorded, Response: response})
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+
+	// the response is the one from the API, not the scrubbed one, as the provider needs the real values.
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	return resp, nil
+}
+
+// save writes the cassette.
+func (r *Recorder) save() error {
+	data, err := yaml.Marshal(r.cassette)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(r.path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing cassette: %w", err)
+	}
+
+	return nil
+}
+
+// readBody reads the body of the request, and replaces it so it can be read again.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.Body.Close(); err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// newResponse returns the recorded response as a response to the request.
+func newResponse(req *http.Request, recorded Response) *http.Response {
+	header := http.Header{}
+	if recorded.ContentType != "" {
+		header.Set("Content-Type", recorded.ContentType)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
+
+// scrubRequest returns the request as it is recorded, without the API key, client ID and passwords,
+// and with the query and form values sorted.
+func scrubRequest(req *http.Request, body []byte) Request {
+	recorded := Request{Method: req.Method, URL: req.URL.Path}
+	if query := scrubValues(req.URL.Query()); len(query) > 0 {
+		recorded.URL += "?" + query.Encode()
+	}
+
+	recorded.Body = scrubAPIKey(req, string(body))
+	if form, err := url.ParseQuery(string(body)); err == nil && len(body) > 0 {
+		recorded.Body = scrubValues(form).Encode()
+	}
+
+	return recorded
+}
+
+// scrubValues replaces the API key, client ID and passwords in form encoded values, such as a query or a request body.
+func scrubValues(values url.Values) url.Values {
+	for key := range values {
+		switch {
+		case key == "apikey":
+			values.Set(key, ScrubbedAPIKey)
+		case key == "client_id":
+			values.Set(key, ScrubbedClientID)
+		case strings.Contains(strings.ToLower(key), "password"):
+			values.Set(key, ScrubbedPassword)
+		}
+	}
+
+	return values
+}
+
+// scrubResponse removes the API key and client ID of the request, and passwords, from the response body.
+func scrubResponse(req *http.Request, body string) string {
+	body = scrubAPIKey(req, body)
+
+	if clientID := req.URL.Query().Get("client_id"); clientID != "" {
+		body = clientIDField(clientID).ReplaceAllString(body, `${1}`+ScrubbedClientID+`${2}`)
+	}
+
+	return passwordField.ReplaceAllString(body, `${1}"`+ScrubbedPassword+`"`)
+}
+
+// scrubAPIKey removes the API key of the request from the text.
+func scrubAPIKey(req *http.Request, text string) string {
+	apiKey := req.URL.Query().Get("apikey")
+	if apiKey == "" {
+		return text
+	}
+
+	return strings.ReplaceAll(text, apiKey, ScrubbedAPIKey)
+}
+
+// clientIDField matches the client_id fields in a JSON body with the client ID, quoted or not.
+func clientIDField(clientID string) *regexp.Regexp {
+	return regexp.MustCompile(`("client_id"\s*:\s*"?)` + regexp.QuoteMeta(clientID) + `("|\b)`)
+}
@@ -0,0 +1,104 @@
+package recorder_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sitehostnz/gosh/pkg/api/cloud/db/user"
+	"github.com/sitehostnz/gosh/pkg/api/server"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/recorder"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "cassette.yaml")
+
+	api := mockapi.NewServer("98765", "recorded-api-key")
+	t.Cleanup(api.Close)
+
+	rec, err := recorder.New(cassette, recorder.ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorded := provisionServer(t, helper.Config{ClientID: "98765", APIKey: "recorded-api-key", APIEndpoint: api.Endpoint(), Transport: rec})
+	if recorded.password == "" || recorded.password == recorder.ScrubbedPassword {
+		t.Fatalf("recording returned password %q, want the password from the API", recorded.password)
+	}
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"98765", "recorded-api-key", recorded.password, "database-password"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	// the API is closed, so the replay can only come from the cassette, and it is made with other credentials.
+	api.Close()
+
+	rec, err = recorder.New(cassette, recorder.ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := provisionServer(t, helper.Config{ClientID: "11111", APIKey: "replayed-api-key", APIEndpoint: api.Endpoint(), Transport: rec})
+	if replayed.name != recorded.name {
+		t.Errorf("replayed server %q, recorded %q", replayed.name, recorded.name)
+	}
+
+	if replayed.password != recorder.ScrubbedPassword {
+		t.Errorf("replayed password %q, want %q", replayed.password, recorder.ScrubbedPassword)
+	}
+}
+
+// provisioned is what provisionServer got back from the API.
+type provisioned struct {
+	name     string
+	password string
+}
+
+// provisionServer makes requests with the client ID in the form body, a password in the form body, and a password in the response.
+func provisionServer(t *testing.T, config helper.Config) provisioned {
+	t.Helper()
+
+	ctx := context.Background()
+
+	conf, diags := config.Client(ctx)
+	if diags.HasError() {
+		t.Fatalf("error creating client: %v", diags)
+	}
+
+	created, err := server.New(conf.Client).Create(ctx, server.CreateRequest{
+		Label:       "web",
+		Location:    "AKLCITY",
+		ProductCode: "XENLIT",
+		Image:       "ubuntu-jammy-pvh.amd64",
+	})
+	if err != nil {
+		t.Fatalf("error creating server: %s", err)
+	}
+
+	if _, err := user.New(conf.Client).Add(ctx, user.AddRequest{
+		ServerName: created.Return.Name,
+		MySQLHost:  "mysql8",
+		Username:   "app",
+		Password:   "database-password",
+	}); err != nil {
+		t.Fatalf("error adding database user: %s", err)
+	}
+
+	got, err := server.New(conf.Client).Get(ctx, server.GetRequest{ServerName: created.Return.Name})
+	if err != nil {
+		t.Fatalf("error getting server: %s", err)
+	}
+
+	return provisioned{name: got.Server.Name, password: created.Return.Password}
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/sitehostnz/gosh/pkg/api"
+	"github.com/sitehostnz/gosh/pkg/api/server"
+	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/gosh/pkg/shtypes"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+)
+
+// Changing the power state, the disks or reinstalling a server, paging through the servers,
+// and listing the locations, products and images servers are built from, which GoSH doesn't cover, see helper.Post.
+type (
+	// listServersOptions represents the page of servers to list.
+	listServersOptions struct {
+		PageNumber int `url:"filters[page_number],omitempty"`
+	}
+
+	// changeStateRequest represents a request to start, stop or reboot a server.
+	changeStateRequest struct {
+		ServerName string `json:"name"`
+		State      string `json:"state"`
+	}
+
+	// diskRequest represents a request to add or resize a disk, the change is staged until the disk changes are committed.
+	diskRequest struct {
+		ServerName string `json:"name"`
+		Label      string `json:"label"`
+		Size       int    `json:"size"`
+	}
+
+	// reinstallRequest represents a request to reinstall a server from an image.
+	reinstallRequest struct {
+		ServerName string   `json:"name"`
+		Image      string   `json:"image"`
+		SSHKeys    []string `json:"ssh_keys"`
+	}
+
+	// reinstallResponse is the response from reinstalling a server.
+	reinstallResponse struct {
+		Return struct {
+			models.Job `json:"job"`
+			Password   string `json:"password"`
+		} `json:"return"`
+		models.APIResponse
+	}
+
+	// location is a location servers can be built in.
+	location struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+
+	// product is a product servers can be built on.
+	product struct {
+		Code  string              `json:"code"`
+		Name  string              `json:"name"`
+		Type  string              `json:"type"`
+		Cores shtypes.MaybeBigInt `json:"cores"`
+		RAM   shtypes.MaybeBigInt `json:"ram"`
+		Disk  shtypes.MaybeBigInt `json:"disk"`
+		Price string              `json:"price"`
+	}
+
+	// image is an image servers can be built from.
+	image struct {
+		Code  string `json:"code"`
+		Label string `json:"label"`
+	}
+
+	// locationsResponse is the response from listing the locations.
+	locationsResponse struct {
+		Return []location `json:"return"`
+		models.APIResponse
+	}
+
+	// productsResponse is the response from listing the products.
+	productsResponse struct {
+		Return []product `json:"return"`
+		models.APIResponse
+	}
+
+	// imagesResponse is the response from listing the images.
+	imagesResponse struct {
+		Return []image `json:"return"`
+		models.APIResponse
+	}
+)
+
+// changeState starts, stops or reboots a server.
+func changeState(ctx context.Context, client *api.Client, request changeStateRequest) (response helper.JobResponse, err error) {
+	keys := []string{
+		"name",
+		"state",
+	}
+
+	values := url.Values{}
+	values.Add("name", request.ServerName)
+	values.Add("state", request.State)
+
+	err = helper.Post(ctx, client, "server/change_state.json", keys, values, &response)
+
+	return response, err
+}
+
+// addDisk stages a new disk on a server.
+func addDisk(ctx context.Context, client *api.Client, request diskRequest) (response models.APIResponse, err error) {
+	return postDiskRequest(ctx, client, "server/add_disk.json", request)
+}
+
+// resizeDisk stages a new size for a disk on a server.
+func resizeDisk(ctx context.Context, client *api.Client, request diskRequest) (response models.APIResponse, err error) {
+	return postDiskRequest(ctx, client, "server/resize_disk.json", request)
+}
+
+// postDiskRequest sends a disk request to the endpoint.
+func postDiskRequest(ctx context.Context, client *api.Client, uri string, request diskRequest) (response models.APIResponse, err error) {
+	keys := []string{
+		"name",
+		"label",
+		"size",
+	}
+
+	values := url.Values{}
+	values.Add("name", request.ServerName)
+	values.Add("label", request.Label)
+	values.Add("size", strconv.Itoa(request.Size))
+
+	err = helper.Post(ctx, client, uri, keys, values, &response)
+
+	return response, err
+}
+
+// listServers lists a page of the servers.
+func listServers(ctx context.Context, client *api.Client, opt listServersOptions) (response server.ListResponse, err error) {
+	err = helper.Get(ctx, client, "server/list_servers.json", opt, &response)
+
+	return response, err
+}
+
+// listLocations lists the locations servers can be built in.
+func listLocations(ctx context.Context, client *api.Client) (response locationsResponse, err error) {
+	err = helper.Get(ctx, client, "server/list_locations.json", nil, &response)
+
+	return response, err
+}
+
+// listProducts lists the products servers can be built on.
+func listProducts(ctx context.Context, client *api.Client) (response productsResponse, err error) {
+	err = helper.Get(ctx, client, "server/list_products.json", nil, &response)
+
+	return response, err
+}
+
+// listImages lists the images servers can be built from.
+func listImages(ctx context.Context, client *api.Client) (response imagesResponse, err error) {
+	err = helper.Get(ctx, client, "server/list_images.json", nil, &response)
+
+	return response, err
+}
+
+// reinstall reinstalls a server from an image, keeping its name and IP addresses.
+func reinstall(ctx context.Context, client *api.Client, request reinstallRequest) (response reinstallResponse, err error) {
+	keys := []string{
+		"name",
+		"image",
+		"params[ssh_keys][]",
+	}
+
+	values := url.Values{}
+	values.Add("name", request.ServerName)
+	values.Add("image", request.Image)
+	for _, key := range request.SSHKeys {
+		values.Add("params[ssh_keys][]", key)
+	}
+
+	err = helper.Post(ctx, client, "server/reinstall.json", keys, values, &response)
+
+	return response, err
+}
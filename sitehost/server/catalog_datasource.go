@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+)
+
+// LocationsDataSource returns a schema with the function to list the locations Servers can be deployed in.
+func LocationsDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readLocationsDataSource,
+		Schema:      locationsDataSourceSchema,
+	}
+}
+
+// ProductsDataSource returns a schema with the function to list the products Servers can be deployed on.
+func ProductsDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readProductsDataSource,
+		Schema:      productsDataSourceSchema,
+	}
+}
+
+// ImagesDataSource returns a schema with the function to list the images Servers can be deployed from.
+func ImagesDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: readImagesDataSource,
+		Schema:      imagesDataSourceSchema,
+	}
+}
+
+// readLocationsDataSource is a function to list the server locations.
+func readLocationsDataSource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	response, err := listLocations(ctx, conf.Client)
+	if err != nil {
+		return diag.Errorf("Error retrieving server locations: %s", err)
+	}
+
+	locations := make([]map[string]any, 0, len(response.Return))
+	for _, l := range response.Return {
+		locations = append(locations, map[string]any{
+			"code": l.Code,
+			"name": l.Name,
+		})
+	}
+
+	d.SetId("locations")
+
+	if err := d.Set("locations", locations); err != nil {
+		return diag.Errorf("Error retrieving server locations: %s", err)
+	}
+
+	return nil
+}
+
+// readProductsDataSource is a function to list the server products.
+func readProductsDataSource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	response, err := listProducts(ctx, conf.Client)
+	if err != nil {
+		return diag.Errorf("Error retrieving server products: %s", err)
+	}
+
+	products := make([]map[string]any, 0, len(response.Return))
+	for _, p := range response.Return {
+		products = append(products, map[string]any{
+			"code":  p.Code,
+			"name":  p.Name,
+			"type":  p.Type,
+			"cores": int(p.Cores),
+			"ram":   int(p.RAM),
+			"disk":  int(p.Disk),
+			"price": p.Price,
+		})
+	}
+
+	d.SetId("products")
+
+	if err := d.Set("products", products); err != nil {
+		return diag.Errorf("Error retrieving server products: %s", err)
+	}
+
+	return nil
+}
+
+// readImagesDataSource is a function to list the server images.
+func readImagesDataSource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	response, err := listImages(ctx, conf.Client)
+	if err != nil {
+		return diag.Errorf("Error retrieving server images: %s", err)
+	}
+
+	images := make([]map[string]any, 0, len(response.Return))
+	for _, i := range response.Return {
+		images = append(images, map[string]any{
+			"code":  i.Code,
+			"label": i.Label,
+		})
+	}
+
+	d.SetId("images")
+
+	if err := d.Set("images", images); err != nil {
+		return diag.Errorf("Error retrieving server images: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package server
+
+const (
+	// powerStateRunning is the power_state of a server that is on.
+	powerStateRunning = "running"
+	// powerStateStopped is the power_state of a server that is off.
+	powerStateStopped = "stopped"
+
+	// serverStateOn is the state the API reports for a server that is on.
+	serverStateOn = "On"
+	// serverStateOff is the state the API reports for a server that is off.
+	serverStateOff = "Off"
+
+	// powerActionStart starts a server.
+	powerActionStart = "start"
+	// powerActionStop stops a server.
+	powerActionStop = "stop"
+	// powerActionReboot reboots a server.
+	powerActionReboot = "reboot"
+)
+
+// powerActionStates maps the power actions to the states the change state endpoint takes.
+var powerActionStates = map[string]string{
+	powerActionStart:  "power_on",
+	powerActionStop:   "power_off",
+	powerActionReboot: "reboot",
+}
@@ -3,9 +3,13 @@ package server
 import (
 	"context"
 	"fmt"
+	"regexp"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/server"
+	"github.com/sitehostnz/gosh/pkg/api/server/firewall/securitygroups"
+	"github.com/sitehostnz/gosh/pkg/models"
 	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
 )
 
@@ -17,9 +21,16 @@ func DataSource() *schema.Resource {
 	}
 }
 
-// readDataSource is a function to read servers (not implemented).
-func readDataSource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+// ListDataSource returns a schema with the function to list Servers, with/without a filter.
+func ListDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: listDataSource,
+		Schema:      serversDataSourceSchema,
+	}
+}
 
+// readDataSource is a function to read a server.
+func readDataSource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -34,15 +45,105 @@ func readDataSource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 	if err != nil {
 		return diag.Errorf("Error retrieving server: %s", err)
 	}
-	d.SetId(resp.Server.Name)
-	
+
 	if !resp.Status {
 		return diag.Errorf("Error retrieving server: %s", resp.Msg)
 	}
 
+	d.SetId(resp.Server.Name)
+
 	if err := setServerAttributes(d, resp.Server); err != nil {
 		return diag.FromErr(err)
 	}
 
 	return nil
 }
+
+// listDataSource is a function to list the servers on the account, filtered by label, location, product and security group.
+func listDataSource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	var labelRegex *regexp.Regexp
+	if v, ok := d.GetOk("label_regex"); ok {
+		r, err := regexp.Compile(fmt.Sprint(v))
+		if err != nil {
+			return diag.Errorf("Error compiling label_regex: %s", err)
+		}
+		labelRegex = r
+	}
+
+	location, filterLocation := d.GetOk("location")
+	productCode, filterProduct := d.GetOk("product_code")
+
+	// the security group knows its servers, the servers don't know their security groups.
+	var groupServers map[string]bool
+	if group, ok := d.GetOk("security_group"); ok {
+		response, err := securitygroups.New(conf.Client).Get(ctx, securitygroups.GetRequest{Name: fmt.Sprint(group)})
+		if err != nil {
+			return diag.Errorf("Error retrieving security group: %s", err)
+		}
+
+		groupServers = make(map[string]bool, len(response.Return.Servers))
+		for _, s := range response.Return.Servers {
+			groupServers[s.Name] = true
+		}
+	}
+
+	var all []models.Server
+	for page := 1; ; page++ {
+		response, err := listServers(ctx, conf.Client, listServersOptions{PageNumber: page})
+		if err != nil {
+			return diag.Errorf("Error retrieving servers: %s", err)
+		}
+
+		if !response.Status {
+			return diag.Errorf("Error retrieving servers: %s", response.Msg)
+		}
+
+		all = append(all, response.Return.Servers...)
+
+		if page >= response.Return.TotalPages {
+			break
+		}
+	}
+
+	servers := []map[string]any{}
+	for _, s := range all {
+		switch {
+		case labelRegex != nil && !labelRegex.MatchString(s.Label):
+			continue
+		case filterLocation && s.LocationCode != fmt.Sprint(location):
+			continue
+		case filterProduct && s.ProductCode != fmt.Sprint(productCode):
+			continue
+		case groupServers != nil && !groupServers[s.Name]:
+			continue
+		}
+
+		ips := make([]string, 0, len(s.Ips))
+		for _, ip := range s.Ips {
+			ips = append(ips, ip.IPAddr)
+		}
+
+		servers = append(servers, map[string]any{
+			"name":         s.Name,
+			"label":        s.Label,
+			"location":     s.LocationCode,
+			"product_code": s.ProductCode,
+			"state":        s.State,
+			"power_state":  powerState(s.State),
+			"ips":          ips,
+		})
+	}
+
+	d.SetId("servers")
+
+	if err := d.Set("servers", servers); err != nil {
+		return diag.Errorf("Error retrieving servers: %s", err)
+	}
+
+	return nil
+}
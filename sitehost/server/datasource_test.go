@@ -0,0 +1,45 @@
+package server_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+)
+
+func TestAccServersDataSource(t *testing.T) {
+	api := acctest.NewAPI(t)
+	// one server to a page, so the data source has to read every page to find them all.
+	api.SetPageSize(1)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.ProviderConfig(api) + `
+resource "sitehost_server" "test" {
+  count        = 3
+  label        = "web-${count.index}"
+  location     = "AKLCITY"
+  product_code = "XENLIT"
+  image        = "ubuntu-jammy-pvh.amd64"
+}
+
+data "sitehost_servers" "all" {
+  depends_on = [sitehost_server.test]
+}
+
+data "sitehost_servers" "last" {
+  label_regex = "^web-2$"
+  depends_on  = [sitehost_server.test]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sitehost_servers.all", "servers.#", "3"),
+					resource.TestCheckResourceAttr("data.sitehost_servers.last", "servers.#", "1"),
+					resource.TestCheckResourceAttrPair("data.sitehost_servers.last", "servers.0.name", "sitehost_server.test.2", "name"),
+				),
+			},
+		},
+	})
+}
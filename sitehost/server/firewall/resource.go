@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/server/firewall"
@@ -18,6 +19,8 @@ func Resource() *schema.Resource {
 		ReadContext:   readResource,
 		UpdateContext: updateResource,
 		DeleteContext: deleteResource,
+		Timeouts:      helper.JobTimeouts(),
+		CustomizeDiff: claimFirewall,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -32,19 +35,19 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Di
 		return diag.Errorf("failed to convert meta object")
 	}
 
-	client := firewall.New(conf.Client)
-
-	resp, err := client.Get(ctx, firewall.GetRequest{
-		ServerName: d.Get("server").(string),
-	})
+	// the ID is the server name, which is all an import has.
+	groups, err := GetGroups(ctx, conf, d.Id())
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "server firewall")
+		}
 		return diag.Errorf("Error reading server: %s", err)
 	}
 
-	groups := make([]string, len(resp.Return))
-	for i, group := range resp.Return {
-		groups[i] = group.Group
+	if err := d.Set("server", d.Id()); err != nil {
+		return diag.FromErr(err)
 	}
+
 	if err := d.Set("groups", groups); err != nil {
 		return diag.FromErr(err)
 	}
@@ -54,6 +57,8 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Di
 
 // updateResource is a function to update the firewall of a server.
 func updateResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogServer)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -79,16 +84,75 @@ func updateResource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 		return diags
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogServer, "Updated server firewall", map[string]any{"server_name": serverName, "groups": groups})
+
 	d.SetId(serverName)
 	return nil
 }
 
+// UpdateGroups applies the security groups to the firewall of a server, in order, and waits for the job to finish.
+// The sitehost_server resource uses this to manage its securitygroups.
+func UpdateGroups(ctx context.Context, conf *helper.CombinedConfig, serverName string, groups []string) diag.Diagnostics {
+	response, diags := updateFirewallGroups(ctx, firewall.New(conf.Client), serverName, groups)
+	if diags != nil {
+		return diags
+	}
+
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// GetGroups returns the security groups on the firewall of a server, in order.
+func GetGroups(ctx context.Context, conf *helper.CombinedConfig, serverName string) ([]string, error) {
+	resp, err := firewall.New(conf.Client).Get(ctx, firewall.GetRequest{
+		ServerName: serverName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, len(resp.Return))
+	for i, group := range resp.Return {
+		groups[i] = group.Group
+	}
+
+	return groups, nil
+}
+
+// ClaimFirewall claims the firewall of a server for a kind of resource, and errors if another kind already manages it.
+func ClaimFirewall(conf *helper.CombinedConfig, serverName string, owner string) error {
+	if current, ok := conf.FirewallClaims.Claim(serverName, owner); !ok {
+		return fmt.Errorf("the security groups of server %s are managed by both %s and %s, only one of them can manage them", serverName, current, owner)
+	}
+
+	return nil
+}
+
+// claimFirewall makes sure the sitehost_server resource isn't also managing the security groups of the server.
+func claimFirewall(_ context.Context, d *schema.ResourceDiff, meta any) error {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return fmt.Errorf("failed to convert meta object")
+	}
+
+	if !d.NewValueKnown("server") {
+		return nil
+	}
+
+	return ClaimFirewall(conf, fmt.Sprint(d.Get("server")), "sitehost_server_firewall")
+}
+
 // deleteResource is a function to clear the firewall of a server.
 func deleteResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogServer)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -106,10 +170,12 @@ func deleteResource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 		return diags
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogServer, "Cleared server firewall", map[string]any{"server_name": serverName})
+
 	// Clear the ID when the resource is deleted
 	d.SetId("")
 	return nil
@@ -0,0 +1,102 @@
+package firewall_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/server/firewall"
+)
+
+func TestAccServerFirewall(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServerFirewallConfig(api, "sitehost_server_security_group.web.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("sitehost_server_firewall.test", "id", "sitehost_server.test", "name"),
+					resource.TestCheckResourceAttr("sitehost_server_firewall.test", "groups.#", "1"),
+					resource.TestCheckResourceAttrPair("sitehost_server_firewall.test", "groups.0", "sitehost_server_security_group.web", "name"),
+				),
+			},
+			{
+				Config: testAccServerFirewallConfig(api, "sitehost_server_security_group.mail.name", "sitehost_server_security_group.web.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_server_firewall.test", "groups.#", "2"),
+					resource.TestCheckResourceAttrPair("sitehost_server_firewall.test", "groups.0", "sitehost_server_security_group.mail", "name"),
+					resource.TestCheckResourceAttrPair("sitehost_server_firewall.test", "groups.1", "sitehost_server_security_group.web", "name"),
+				),
+			},
+			{
+				ResourceName:      "sitehost_server_firewall.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// destroying the firewall clears the security groups from the server, which is left.
+				Config: testAccServerFirewallConfig(api),
+				Check:  testAccCheckServerFirewallCleared(t, api, "sitehost_server.test"),
+			},
+		},
+	})
+}
+
+// testAccCheckServerFirewallCleared checks the server has no security groups on its firewall.
+func testAccCheckServerFirewallCleared(t *testing.T, api *mockapi.Server, name string) resource.TestCheckFunc {
+	t.Helper()
+
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found in state", name)
+		}
+
+		groups, err := firewall.GetGroups(t.Context(), acctest.Client(t, api), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(groups) > 0 {
+			return fmt.Errorf("server %s still has the security groups %s", rs.Primary.ID, strings.Join(groups, ", "))
+		}
+
+		return nil
+	}
+}
+
+// testAccServerFirewallConfig returns the config with the groups on the firewall, or without the firewall when there are none.
+func testAccServerFirewallConfig(api *mockapi.Server, groups ...string) string {
+	config := acctest.ProviderConfig(api) + `
+resource "sitehost_server" "test" {
+  label        = "web"
+  location     = "AKLCITY"
+  product_code = "XENLIT"
+  image        = "ubuntu-jammy-pvh.amd64"
+}
+
+resource "sitehost_server_security_group" "web" {
+  label = "web"
+}
+
+resource "sitehost_server_security_group" "mail" {
+  label = "mail"
+}
+`
+	if len(groups) == 0 {
+		return config
+	}
+
+	return config + fmt.Sprintf(`
+resource "sitehost_server_firewall" "test" {
+  server = sitehost_server.test.name
+  groups = [%s]
+}
+`, strings.Join(groups, ", "))
+}
@@ -7,9 +7,10 @@ import (
 // resourceSchema is the schema with values for a Server Firewall resource.
 var resourceSchema = map[string]*schema.Schema{
 	"server": {
-		Type:        schema.TypeString,
-		Required:    true,
-		Description: "The name of the server to manage firewall rules for.",
+		Type:     schema.TypeString,
+		Required: true,
+		Description: "The name of the server to manage firewall rules for. Don't also manage its security groups with `securitygroups` on `sitehost_server`, " +
+			"that is only caught when planning both in the same configuration, once the server exists.",
 	},
 	"groups": {
 		Type:        schema.TypeList,
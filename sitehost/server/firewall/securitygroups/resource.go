@@ -4,8 +4,8 @@ package securitygroups
 import (
 	"context"
 	"fmt"
-	"log"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/server/firewall/securitygroups"
@@ -19,6 +19,7 @@ func Resource() *schema.Resource {
 		ReadContext:   readResource,
 		UpdateContext: updateResource,
 		DeleteContext: deleteResource,
+		Timeouts:      helper.JobTimeouts(),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -28,6 +29,8 @@ func Resource() *schema.Resource {
 
 // createResource is a function to create a new security group.
 func createResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogServer)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -54,7 +57,7 @@ func createResource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Security Group Name: %s", d.Id())
+	tflog.SubsystemInfo(ctx, helper.LogServer, "Created security group", map[string]any{"security_group": d.Id()})
 
 	if d.Get("rules_in") != nil || d.Get("rules_out") != nil {
 		if err := updateResource(ctx, d, meta); err != nil {
@@ -67,6 +70,8 @@ func createResource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 
 // updateResource is a function to update a security group.
 func updateResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogServer)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -91,10 +96,12 @@ func updateResource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 		return diag.Errorf("Error updating security group: %s", response.Msg)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogServer, "Updated security group", map[string]any{"security_group": d.Id()})
+
 	return readResource(ctx, d, meta)
 }
 
@@ -138,6 +145,8 @@ func processRules(rulesRaw interface{}, direction string) []securitygroups.Updat
 
 // deleteResource is a function to delete a security group.
 func deleteResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogServer)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -155,6 +164,8 @@ func deleteResource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 		return diag.Errorf("Error deleting security group: %s", resp.Msg)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogServer, "Deleted security group", map[string]any{"security_group": d.Id()})
+
 	return nil
 }
 
@@ -170,6 +181,9 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Di
 		Name: d.Id(),
 	})
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "security group")
+		}
 		return diag.Errorf("Error reading security group: %s", err)
 	}
 
@@ -177,6 +191,10 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Di
 		return diag.Errorf("Error reading security group: %s", resp.Msg)
 	}
 
+	if err := d.Set("name", d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
 	if err := d.Set("label", resp.Return.Label); err != nil {
 		return diag.FromErr(err)
 	}
@@ -0,0 +1,85 @@
+package securitygroups_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sitehostnz/gosh/pkg/api/server/firewall/securitygroups"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+)
+
+func TestAccSecurityGroup(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		CheckDestroy: acctest.CheckDestroy(t, api, "sitehost_server_security_group", func(ctx context.Context, conf *helper.CombinedConfig, rs *terraform.ResourceState) (bool, error) {
+			_, err := securitygroups.New(conf.Client).Get(ctx, securitygroups.GetRequest{Name: rs.Primary.ID})
+			return err == nil, err
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSecurityGroupConfig(api, "web", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("sitehost_server_security_group.test", "name"),
+					resource.TestCheckResourceAttr("sitehost_server_security_group.test", "label", "web"),
+					resource.TestCheckResourceAttr("sitehost_server_security_group.test", "rules_in.#", "1"),
+					resource.TestCheckResourceAttr("sitehost_server_security_group.test", "rules_in.0.dest_port", "443"),
+					resource.TestCheckResourceAttr("sitehost_server_security_group.test", "rules_in.0.enabled", "true"),
+					resource.TestCheckResourceAttr("sitehost_server_security_group.test", "rules_out.#", "0"),
+				),
+			},
+			{
+				Config: testAccSecurityGroupConfig(api, "web-and-mail", `
+  rules_in {
+    action    = "ACCEPT"
+    protocol  = "tcp"
+    src_ip    = "192.0.2.0/24"
+    dest_port = "80"
+    enabled   = false
+  }
+
+  rules_out {
+    action    = "ACCEPT"
+    protocol  = "tcp"
+    dest_ip   = "0.0.0.0/0"
+    dest_port = "25"
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_server_security_group.test", "label", "web-and-mail"),
+					resource.TestCheckResourceAttr("sitehost_server_security_group.test", "rules_in.#", "2"),
+					resource.TestCheckResourceAttr("sitehost_server_security_group.test", "rules_in.1.src_ip", "192.0.2.0/24"),
+					resource.TestCheckResourceAttr("sitehost_server_security_group.test", "rules_in.1.enabled", "false"),
+					resource.TestCheckResourceAttr("sitehost_server_security_group.test", "rules_out.#", "1"),
+					resource.TestCheckResourceAttr("sitehost_server_security_group.test", "rules_out.0.dest_port", "25"),
+				),
+			},
+			{
+				ResourceName:      "sitehost_server_security_group.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccSecurityGroupConfig(api *mockapi.Server, label string, rules string) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_server_security_group" "test" {
+  label = %q
+
+  rules_in {
+    action    = "ACCEPT"
+    protocol  = "tcp"
+    src_ip    = "0.0.0.0/0"
+    dest_port = "443"
+  }
+%s}
+`, label, rules)
+}
@@ -0,0 +1,266 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sitehostnz/gosh/pkg/models"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/server/firewall"
+)
+
+// disk is a disk of a server, as declared in a disk block.
+type disk struct {
+	Label string
+	Size  int
+}
+
+// expandDisks converts the disk blocks into disks.
+func expandDisks(v any) []disk {
+	blocks, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+
+	disks := make([]disk, 0, len(blocks))
+	for _, b := range blocks {
+		m, ok := b.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		size, _ := m["size"].(int)
+		disks = append(disks, disk{Label: fmt.Sprint(m["label"]), Size: size})
+	}
+
+	return disks
+}
+
+// partitionDisks converts the partitions of a server, other than swap, into disks.
+func partitionDisks(partitions []models.Partition) []disk {
+	disks := make([]disk, 0, len(partitions))
+	for _, partition := range partitions {
+		if strings.EqualFold(partition.Fstype, "swap") {
+			continue
+		}
+
+		size, err := strconv.Atoi(partition.Size)
+		if err != nil {
+			size = 0
+		}
+
+		disks = append(disks, disk{Label: partition.Name, Size: size})
+	}
+
+	return disks
+}
+
+// flattenPartitionDisks converts the partitions of a server into disk blocks.
+// When managed disks are given, only those are returned, in the same order, so disks we don't manage don't show up as drift.
+func flattenPartitionDisks(partitions []models.Partition, managed []disk) []map[string]any {
+	disks := partitionDisks(partitions)
+
+	if len(managed) > 0 {
+		sizes := make(map[string]int, len(disks))
+		for _, d := range disks {
+			sizes[d.Label] = d.Size
+		}
+
+		disks = disks[:0]
+		for _, m := range managed {
+			if size, ok := sizes[m.Label]; ok {
+				disks = append(disks, disk{Label: m.Label, Size: size})
+			}
+		}
+	}
+
+	ret := make([]map[string]any, 0, len(disks))
+	for _, d := range disks {
+		ret = append(ret, map[string]any{
+			"label": d.Label,
+			"size":  d.Size,
+		})
+	}
+
+	return ret
+}
+
+// stageDiskChanges stages the disks that need to grow or be added, they take effect once the disk changes are committed.
+func stageDiskChanges(ctx context.Context, conf *helper.CombinedConfig, name string, current []disk, want []disk) error {
+	sizes := make(map[string]int, len(current))
+	for _, c := range current {
+		sizes[c.Label] = c.Size
+	}
+
+	for _, w := range want {
+		size, exists := sizes[w.Label]
+		if exists && size >= w.Size {
+			continue
+		}
+
+		request := diskRequest{ServerName: name, Label: w.Label, Size: w.Size}
+
+		var (
+			response models.APIResponse
+			err      error
+		)
+		if exists {
+			response, err = resizeDisk(ctx, conf.Client, request)
+		} else {
+			response, err = addDisk(ctx, conf.Client, request)
+		}
+		if err != nil {
+			return fmt.Errorf("error staging disk %s: %w", w.Label, err)
+		}
+
+		if !response.Status {
+			return fmt.Errorf("error staging disk %s: %s", w.Label, response.Msg)
+		}
+
+		tflog.SubsystemDebug(ctx, helper.LogServer, "Staged server disk", map[string]any{"server_name": name, "disk": w.Label, "size": w.Size, "new": !exists})
+	}
+
+	return nil
+}
+
+// validateDiskChanges rejects disk changes the API can't make, disks can only be grown or added.
+func validateDiskChanges(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if d.Id() == "" || !d.HasChange("disk") || !d.NewValueKnown("disk") {
+		return nil
+	}
+
+	o, n := d.GetChange("disk")
+
+	return checkDiskChanges(d.Id(), expandDisks(o), expandDisks(n))
+}
+
+// checkDiskChanges checks the disks a server is to have against the ones it has.
+// The disks that aren't set are read back, so until the disks are set the current ones are every disk the server has,
+// and the first disks set have to include all of them.
+func checkDiskChanges(name string, current []disk, want []disk) error {
+	sizes := make(map[string]int, len(want))
+	for _, w := range want {
+		sizes[w.Label] = w.Size
+	}
+
+	var missing []string
+	for _, c := range current {
+		size, ok := sizes[c.Label]
+		if !ok {
+			missing = append(missing, c.Label)
+			continue
+		}
+
+		if size < c.Size {
+			return fmt.Errorf("disk %s on server %s can not shrink from %dGB to %dGB", c.Label, name, c.Size, size)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("disks can not be removed from server %s, the disk blocks have to list every disk it has, "+
+			"add %s", name, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// validateCatalog checks the location, product and image against the catalogs, and rejects product downgrades.
+// The checks are best effort, if a catalog can't be fetched the API gets the final say when applying.
+// Custom and private images aren't in the public image catalog, so an unknown image is only a warning.
+func validateCatalog(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return fmt.Errorf("failed to convert meta object")
+	}
+
+	ctx = helper.LogContext(ctx, helper.LogServer)
+
+	if d.HasChange("location") && d.NewValueKnown("location") {
+		response, err := listLocations(ctx, conf.Client)
+		if err != nil {
+			tflog.SubsystemWarn(ctx, helper.LogServer, "Unable to validate the server location", map[string]any{"error": err.Error()})
+		} else {
+			code := fmt.Sprint(d.Get("location"))
+			if !helper.Has(response.Return, func(l location) bool { return l.Code == code }) {
+				return fmt.Errorf("location %s is not one of the locations in sitehost_server_locations", code)
+			}
+		}
+	}
+
+	if d.HasChange("image") && d.NewValueKnown("image") {
+		response, err := listImages(ctx, conf.Client)
+		if err != nil {
+			tflog.SubsystemWarn(ctx, helper.LogServer, "Unable to validate the server image", map[string]any{"error": err.Error()})
+		} else {
+			code := fmt.Sprint(d.Get("image"))
+			if !helper.Has(response.Return, func(i image) bool { return i.Code == code }) {
+				tflog.SubsystemWarn(ctx, helper.LogServer, "The server image is not one of the images in sitehost_server_images", map[string]any{"image": code})
+			}
+		}
+	}
+
+	if d.HasChange("product_code") && d.NewValueKnown("product_code") {
+		response, err := listProducts(ctx, conf.Client)
+		if err != nil {
+			tflog.SubsystemWarn(ctx, helper.LogServer, "Unable to validate the server product", map[string]any{"error": err.Error()})
+			return nil
+		}
+
+		o, n := d.GetChange("product_code")
+		newCode, oldCode := fmt.Sprint(n), fmt.Sprint(o)
+
+		if !helper.Has(response.Return, func(p product) bool { return p.Code == newCode }) {
+			return fmt.Errorf("product %s is not one of the products in sitehost_server_products", newCode)
+		}
+
+		// a new server can be on any product, an existing one can only be upgraded.
+		if d.Id() == "" || oldCode == "" {
+			return nil
+		}
+
+		oldProduct := helper.First(response.Return, func(p product) bool { return p.Code == oldCode })
+		newProduct := helper.First(response.Return, func(p product) bool { return p.Code == newCode })
+		if oldProduct.Code == "" {
+			return nil
+		}
+
+		if newProduct.Cores < oldProduct.Cores || newProduct.RAM < oldProduct.RAM || newProduct.Disk < oldProduct.Disk {
+			return fmt.Errorf("server %s can not be downgraded from %s to %s, servers can only be upgraded", d.Id(), oldCode, newCode)
+		}
+	}
+
+	return nil
+}
+
+// forceNewOnImageChange replaces the server when the image changes, unless it is to be rebuilt in place.
+func forceNewOnImageChange(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if d.Id() == "" || !d.HasChange("image") {
+		return nil
+	}
+
+	// a rebuild comes with a new root password.
+	if rebuild, ok := d.Get("rebuild_on_image_change").(bool); ok && rebuild {
+		return d.SetNewComputed("password")
+	}
+
+	return d.ForceNew("image")
+}
+
+// claimFirewall makes sure the sitehost_server_firewall resource isn't also managing the security groups of the server.
+func claimFirewall(_ context.Context, d *schema.ResourceDiff, meta any) error {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return fmt.Errorf("failed to convert meta object")
+	}
+
+	// the name of a new server isn't known until it is created.
+	if d.Id() == "" || len(helper.ToStringList(d.Get("securitygroups"))) == 0 {
+		return nil
+	}
+
+	return firewall.ClaimFirewall(conf, d.Id(), "sitehost_server")
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckDiskChanges(t *testing.T) {
+	// the disks of a server whose disks were never set, as they are read back.
+	unmanaged := []disk{{Label: "root", Size: 20}, {Label: "data", Size: 50}}
+
+	tests := []struct {
+		name    string
+		current []disk
+		want    []disk
+		err     string
+	}{
+		{
+			name:    "unchanged",
+			current: unmanaged,
+			want:    unmanaged,
+		},
+		{
+			name:    "grow and add",
+			current: unmanaged,
+			want:    []disk{{Label: "root", Size: 40}, {Label: "data", Size: 50}, {Label: "logs", Size: 10}},
+		},
+		{
+			name:    "unmanaged to partial",
+			current: unmanaged,
+			want:    []disk{{Label: "logs", Size: 10}},
+			err:     "have to list every disk it has, add root, data",
+		},
+		{
+			name:    "remove",
+			current: unmanaged,
+			want:    []disk{{Label: "root", Size: 20}},
+			err:     "add data",
+		},
+		{
+			name:    "shrink",
+			current: unmanaged,
+			want:    []disk{{Label: "root", Size: 10}, {Label: "data", Size: 50}},
+			err:     "can not shrink from 20GB to 10GB",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDiskChanges("ch-server1", tt.current, tt.want)
+
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("unexpected error: %s", err)
+			case tt.err != "" && err == nil:
+				t.Errorf("got no error, want %q", tt.err)
+			case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+				t.Errorf("got error %q, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestPowerState(t *testing.T) {
+	tests := map[string]string{
+		"On":        powerStateRunning,
+		"off":       powerStateStopped,
+		"Off":       powerStateStopped,
+		"Rebooting": "Rebooting",
+		"":          "",
+	}
+
+	for state, want := range tests {
+		if got := powerState(state); got != want {
+			t.Errorf("powerState(%q) = %q, want %q", state, got, want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sitehostnz/gosh/pkg/api/server"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+)
+
+// PowerResource returns a schema with the operations for a Server Power action.
+// Creating it starts, stops or reboots the server, changing the action or triggers runs it again.
+func PowerResource() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createPowerResource,
+		ReadContext:   readPowerResource,
+		DeleteContext: deletePowerResource,
+		Timeouts:      helper.JobTimeouts(),
+		Schema:        resourcePowerSchema,
+	}
+}
+
+// createPowerResource is a function to start, stop or reboot a server.
+func createPowerResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogServer)
+
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	name := fmt.Sprint(d.Get("server"))
+	action := fmt.Sprint(d.Get("action"))
+
+	if err := changePower(ctx, conf, name, action); err != nil {
+		return diag.Errorf("Error changing server power state: %s", err)
+	}
+
+	d.SetId(name)
+
+	tflog.SubsystemInfo(ctx, helper.LogServer, "Changed server power", map[string]any{"server_name": d.Id(), "action": action})
+
+	return readPowerResource(ctx, d, meta)
+}
+
+// readPowerResource is a function to check the server is still there, the action itself has nothing to read.
+func readPowerResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	conf, ok := meta.(*helper.CombinedConfig)
+	if !ok {
+		return diag.Errorf("failed to convert meta object")
+	}
+
+	client := server.New(conf.Client)
+	if _, err := client.Get(ctx, server.GetRequest{ServerName: d.Id()}); err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "server")
+		}
+		return diag.Errorf("Error retrieving server: %s", err)
+	}
+
+	return nil
+}
+
+// deletePowerResource is a function to forget a power action, the server is left as it is.
+func deletePowerResource(_ context.Context, _ *schema.ResourceData, _ any) diag.Diagnostics {
+	return nil
+}
@@ -4,13 +4,17 @@ package server
 import (
 	"context"
 	"fmt"
-	"log"
+	"strconv"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sitehostnz/gosh/pkg/api/server"
 	"github.com/sitehostnz/gosh/pkg/models"
 	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/server/firewall"
 )
 
 // Resource returns a schema with the operations for a server.
@@ -20,6 +24,8 @@ func Resource() *schema.Resource {
 		ReadContext:   readResource,
 		UpdateContext: updateResource,
 		DeleteContext: deleteResource,
+		Timeouts:      helper.JobTimeouts(),
+		CustomizeDiff: customdiff.All(forceNewOnImageChange, validateDiskChanges, validateCatalog, claimFirewall),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -29,6 +35,8 @@ func Resource() *schema.Resource {
 
 // createResource is a function to create a new server.
 func createResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogServer)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -80,17 +88,46 @@ func createResource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 	}
 
 	// wait for "Completed" status
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Server Name: %s", d.Id())
+	// servers are built with the disks of their plan, any others are added once it is up.
+	if disks := expandDisks(d.Get("disk")); len(disks) > 0 {
+		resp, err := client.Get(ctx, server.GetRequest{ServerName: d.Id()})
+		if err != nil {
+			return diag.Errorf("Error retrieving server: %s", err)
+		}
 
-	return nil
+		if err := stageDiskChanges(ctx, conf, d.Id(), partitionDisks(resp.Server.Partitions), disks); err != nil {
+			return diag.Errorf("Error changing server disks: %s", err)
+		}
+
+		if diags := commitDiskChanges(ctx, conf, client, d); diags.HasError() {
+			return diags
+		}
+	}
+
+	if groups := helper.ToStringList(d.Get("securitygroups")); len(groups) > 0 {
+		if diags := firewall.UpdateGroups(ctx, conf, d.Id(), groups); diags.HasError() {
+			return diags
+		}
+	}
+
+	// servers are built running, so only a stopped server needs another job.
+	if d.Get("power_state") == powerStateStopped {
+		if err := setPowerState(ctx, conf, d.Id(), powerStateStopped); err != nil {
+			return diag.Errorf("Error changing server power state: %s", err)
+		}
+	}
+
+	tflog.SubsystemInfo(ctx, helper.LogServer, "Created server", map[string]any{"server_name": d.Id()})
+
+	return readResource(ctx, d, meta)
 }
 
 // readResource is a function to read a new server.
-func readResource(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+func readResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -98,10 +135,13 @@ func readResource(_ context.Context, d *schema.ResourceData, meta any) diag.Diag
 
 	client := server.New(conf.Client)
 
-	resp, err := client.Get(context.Background(), server.GetRequest{
+	resp, err := client.Get(ctx, server.GetRequest{
 		ServerName: d.Id(),
 	})
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "server")
+		}
 		return diag.Errorf("Error retrieving server: %s", err)
 	}
 
@@ -113,11 +153,30 @@ func readResource(_ context.Context, d *schema.ResourceData, meta any) diag.Diag
 		return diag.FromErr(err)
 	}
 
+	// the security groups are only read back when we are managing them, otherwise sitehost_server_firewall may be.
+	if _, ok := d.GetOk("securitygroups"); ok {
+		groups, err := firewall.GetGroups(ctx, conf, d.Id())
+		if err != nil {
+			return diag.Errorf("Error retrieving server firewall: %s", err)
+		}
+
+		if err := d.Set("securitygroups", groups); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// only the disks we are managing are read back, unless we are not managing any.
+	if err := d.Set("disk", flattenPartitionDisks(resp.Server.Partitions, expandDisks(d.Get("disk")))); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
 // updateResource is a function to update a server.
 func updateResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogServer)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -125,20 +184,127 @@ func updateResource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 
 	client := server.New(conf.Client)
 
+	// the image only changes in place when rebuild_on_image_change is set, otherwise the server is replaced.
+	if d.HasChange("image") {
+		if err := reinstallServer(ctx, conf, d); err != nil {
+			return diag.Errorf("Error reinstalling server: %s", err)
+		}
+
+		tflog.SubsystemInfo(ctx, helper.LogServer, "Reinstalled server", map[string]any{"server_name": d.Id(), "image": d.Get("image")})
+	}
+
+	// the plan and the disks are staged, then committed together.
 	if d.HasChange("product_code") {
-		return upgradePlan(conf, client, d)
+		if diags := upgradePlan(ctx, client, d); diags.HasError() {
+			return diags
+		}
+	}
+
+	if d.HasChange("disk") {
+		o, n := d.GetChange("disk")
+		if err := stageDiskChanges(ctx, conf, d.Id(), expandDisks(o), expandDisks(n)); err != nil {
+			return diag.Errorf("Error changing server disks: %s", err)
+		}
+	}
+
+	if d.HasChanges("product_code", "disk") {
+		if diags := commitDiskChanges(ctx, conf, client, d); diags.HasError() {
+			return diags
+		}
+
+		tflog.SubsystemInfo(ctx, helper.LogServer, "Changed server plan and disks", map[string]any{"server_name": d.Id(), "product_code": d.Get("product_code")})
 	}
 
 	if d.HasChange("label") {
-		return updateLabel(client, d)
+		if diags := updateLabel(ctx, client, d); diags.HasError() {
+			return diags
+		}
+	}
+
+	if d.HasChange("securitygroups") {
+		if diags := firewall.UpdateGroups(ctx, conf, d.Id(), helper.ToStringList(d.Get("securitygroups"))); diags.HasError() {
+			return diags
+		}
 	}
 
+	if d.HasChange("power_state") {
+		if err := setPowerState(ctx, conf, d.Id(), fmt.Sprint(d.Get("power_state"))); err != nil {
+			return diag.Errorf("Error changing server power state: %s", err)
+		}
+
+		tflog.SubsystemInfo(ctx, helper.LogServer, "Changed server power", map[string]any{"server_name": d.Id(), "power_state": d.Get("power_state")})
+	}
+
+	tflog.SubsystemInfo(ctx, helper.LogServer, "Updated server", map[string]any{"server_name": d.Id()})
+
 	return readResource(ctx, d, meta)
 }
 
-// upgradePlan is a function to upgrade and commit a server to the next plan.
-func upgradePlan(conf *helper.CombinedConfig, client *server.Client, d *schema.ResourceData) diag.Diagnostics {
-	res, err := client.Upgrade(context.Background(), server.UpgradeRequest{
+// setPowerState starts or stops a server to match the power_state, and waits for it to get there.
+func setPowerState(ctx context.Context, conf *helper.CombinedConfig, name string, powerState string) error {
+	action := powerActionStart
+	if powerState == powerStateStopped {
+		action = powerActionStop
+	}
+
+	return changePower(ctx, conf, name, action)
+}
+
+// changePower starts, stops or reboots a server, and waits for the job to finish.
+func changePower(ctx context.Context, conf *helper.CombinedConfig, name string, action string) error {
+	response, err := changeState(ctx, conf.Client, changeStateRequest{
+		ServerName: name,
+		State:      powerActionStates[action],
+	})
+	if err != nil {
+		return err
+	}
+
+	if !response.Status {
+		return fmt.Errorf("%s", response.Msg)
+	}
+
+	return helper.WaitForJob(ctx, conf.Client, response.Return.Job)
+}
+
+// reinstallServer is a function to reinstall a server from its image, keeping its name and IP addresses.
+func reinstallServer(ctx context.Context, conf *helper.CombinedConfig, d *schema.ResourceData) error {
+	keys, ok := d.Get("ssh_keys").([]any)
+	if !ok {
+		return fmt.Errorf("failed to convert ssh keys object")
+	}
+
+	sshKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		sshKeys = append(sshKeys, fmt.Sprint(key))
+	}
+
+	response, err := reinstall(ctx, conf.Client, reinstallRequest{
+		ServerName: d.Id(),
+		Image:      fmt.Sprint(d.Get("image")),
+		SSHKeys:    sshKeys,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !response.Status {
+		return fmt.Errorf("%s", response.Msg)
+	}
+
+	// a reinstall comes with a new root password.
+	if response.Return.Password != "" {
+		if err := d.Set("password", response.Return.Password); err != nil {
+			return err
+		}
+	}
+
+	return helper.WaitForJob(ctx, conf.Client, response.Return.Job)
+}
+
+// upgradePlan is a function to stage an upgrade of a server to the next plan, it takes effect once the disk changes are committed.
+func upgradePlan(ctx context.Context, client *server.Client, d *schema.ResourceData) diag.Diagnostics {
+	res, err := client.Upgrade(ctx, server.UpgradeRequest{
 		Name: d.Id(),
 		Plan: fmt.Sprint(d.Get("product_code")),
 	})
@@ -150,18 +316,23 @@ func upgradePlan(conf *helper.CombinedConfig, client *server.Client, d *schema.R
 		return diag.Errorf("Error upgrading server: %s", res.Msg)
 	}
 
-	response, err := client.CommitDiskChanges(context.Background(), server.CommitDiskChangesRequest{
+	return nil
+}
+
+// commitDiskChanges is a function to commit the staged plan and disk changes of a server.
+func commitDiskChanges(ctx context.Context, conf *helper.CombinedConfig, client *server.Client, d *schema.ResourceData) diag.Diagnostics {
+	response, err := client.CommitDiskChanges(ctx, server.CommitDiskChangesRequest{
 		ServerName: d.Id(),
 	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	if !res.Status {
-		return diag.Errorf("Error upgrading server: %s", res.Msg)
+	if !response.Status {
+		return diag.Errorf("Error committing server disk changes: %s", response.Msg)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -169,8 +340,8 @@ func upgradePlan(conf *helper.CombinedConfig, client *server.Client, d *schema.R
 }
 
 // updateLabel is a function to update a label of a server.
-func updateLabel(client *server.Client, d *schema.ResourceData) diag.Diagnostics {
-	res, err := client.Update(context.Background(), server.UpdateRequest{
+func updateLabel(ctx context.Context, client *server.Client, d *schema.ResourceData) diag.Diagnostics {
+	res, err := client.Update(ctx, server.UpdateRequest{
 		Name:  d.Id(),
 		Label: fmt.Sprint(d.Get("label")),
 	})
@@ -186,7 +357,9 @@ func updateLabel(client *server.Client, d *schema.ResourceData) diag.Diagnostics
 }
 
 // deleteResource is a function to delete a server.
-func deleteResource(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+func deleteResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogServer)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -194,7 +367,7 @@ func deleteResource(_ context.Context, d *schema.ResourceData, meta any) diag.Di
 
 	client := server.New(conf.Client)
 
-	response, err := client.Delete(context.Background(), server.DeleteRequest{
+	response, err := client.Delete(ctx, server.DeleteRequest{
 		Name: d.Id(),
 	})
 	if err != nil {
@@ -205,14 +378,87 @@ func deleteResource(_ context.Context, d *schema.ResourceData, meta any) diag.Di
 		return diag.Errorf("Error deleting server: %s", response.Msg)
 	}
 
-	if err := helper.WaitForJob(conf.Client, response.Return.Job); err != nil {
+	if err := helper.WaitForJob(ctx, conf.Client, response.Return.Job); err != nil {
 		return diag.FromErr(err)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogServer, "Deleted server", map[string]any{"server_name": d.Id()})
+
 	return nil
 }
 
+// powerState converts the state the API reports into a power_state.
+// Any other state, such as a server that is still changing, is kept as it is so the plan shows it.
+func powerState(state string) string {
+	switch {
+	case strings.EqualFold(state, serverStateOn):
+		return powerStateRunning
+	case strings.EqualFold(state, serverStateOff):
+		return powerStateStopped
+	default:
+		return state
+	}
+}
+
 // setServerAttributes is a function to set data to a server.
+// The image is not returned by the API, so it is left as it is in the state.
 func setServerAttributes(d *schema.ResourceData, server models.Server) error {
-	return d.Set("name", server.Name)
+	ips := make([]string, 0, len(server.Ips))
+	ipv4 := make([]string, 0, len(server.Ips))
+	ipv6 := make([]string, 0, len(server.Ips))
+	for _, ip := range server.Ips {
+		ips = append(ips, ip.IPAddr)
+
+		switch ip.AddrFamily {
+		case 4:
+			ipv4 = append(ipv4, ip.IPAddr)
+		case 6:
+			ipv6 = append(ipv6, ip.IPAddr)
+		}
+	}
+
+	partitions := make([]map[string]any, 0, len(server.Partitions))
+	for _, partition := range server.Partitions {
+		size, err := strconv.Atoi(partition.Size)
+		if err != nil {
+			size = 0
+		}
+
+		partitions = append(partitions, map[string]any{
+			"name":       partition.Name,
+			"device":     partition.Device,
+			"mountpoint": partition.Mountpoint,
+			"size":       size,
+			"fstype":     partition.Fstype,
+			"type":       partition.Type,
+		})
+	}
+
+	attributes := map[string]any{
+		"name":           server.Name,
+		"label":          server.Label,
+		"location":       server.LocationCode,
+		"location_name":  server.LocationName,
+		"product_code":   server.ProductCode,
+		"product_name":   server.ProductName,
+		"ram":            server.RAM,
+		"cores":          int(server.Cores),
+		"os":             server.Os,
+		"state":          server.State,
+		"power_state":    powerState(server.State),
+		"created":        server.Created,
+		"ips":            ips,
+		"ipv4_addresses": ipv4,
+		"ipv6_addresses": ipv6,
+		"interfaces":     server.Interfaces,
+		"partitions":     partitions,
+	}
+
+	for key, value := range attributes {
+		if err := d.Set(key, value); err != nil {
+			return fmt.Errorf("error setting %s: %w", key, err)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,152 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sitehostnz/gosh/pkg/api/server"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+)
+
+func TestAccServer(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckServerDestroy(t, api),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServerConfig(api, "web", "XENLIT", "running"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("sitehost_server.test", "name"),
+					resource.TestCheckResourceAttrSet("sitehost_server.test", "password"),
+					resource.TestCheckResourceAttr("sitehost_server.test", "label", "web"),
+					resource.TestCheckResourceAttr("sitehost_server.test", "location", "AKLCITY"),
+					resource.TestCheckResourceAttr("sitehost_server.test", "product_code", "XENLIT"),
+					resource.TestCheckResourceAttr("sitehost_server.test", "state", "On"),
+					resource.TestCheckResourceAttr("sitehost_server.test", "ssh_keys.#", "1"),
+					resource.TestCheckResourceAttrPair("sitehost_server.test", "ssh_keys.0", "sitehost_ssh_key.test", "id"),
+				),
+			},
+			{
+				Config: testAccServerConfig(api, "web-renamed", "XENPRO", "stopped"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_server.test", "label", "web-renamed"),
+					resource.TestCheckResourceAttr("sitehost_server.test", "product_code", "XENPRO"),
+					resource.TestCheckResourceAttr("sitehost_server.test", "power_state", "stopped"),
+					resource.TestCheckResourceAttr("sitehost_server.test", "state", "Off"),
+				),
+			},
+			{
+				ResourceName:      "sitehost_server.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// these are only sent when the server is created, the API doesn't return them.
+				ImportStateVerifyIgnore: []string{"image", "password", "ssh_keys", "rebuild_on_image_change"},
+			},
+		},
+	})
+}
+
+func TestAccServerPower(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		// the power action leaves the server as it is when it is destroyed, so it is the server that goes.
+		CheckDestroy: testAccCheckServerDestroy(t, api),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServerPowerConfig(api, "stop", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("sitehost_server_power.test", "id", "sitehost_server.test", "name"),
+					resource.TestCheckResourceAttr("sitehost_server_power.test", "action", "stop"),
+					testAccCheckServerState(t, api, "sitehost_server.test", "Off"),
+				),
+			},
+			{
+				Config: testAccServerPowerConfig(api, "start", "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_server_power.test", "action", "start"),
+					resource.TestCheckResourceAttr("sitehost_server_power.test", "triggers.run", "2"),
+					testAccCheckServerState(t, api, "sitehost_server.test", "On"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckServerDestroy(t *testing.T, api *mockapi.Server) resource.TestCheckFunc {
+	t.Helper()
+
+	return acctest.CheckDestroy(t, api, "sitehost_server", func(ctx context.Context, conf *helper.CombinedConfig, rs *terraform.ResourceState) (bool, error) {
+		_, err := server.New(conf.Client).Get(ctx, server.GetRequest{ServerName: rs.Primary.ID})
+		return err == nil, err
+	})
+}
+
+// testAccCheckServerState checks the state of the server in the API, as the power action doesn't refresh the server resource.
+func testAccCheckServerState(t *testing.T, api *mockapi.Server, name string, state string) resource.TestCheckFunc {
+	t.Helper()
+
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found in state", name)
+		}
+
+		response, err := server.New(acctest.Client(t, api).Client).Get(context.Background(), server.GetRequest{ServerName: rs.Primary.ID})
+		if err != nil {
+			return err
+		}
+
+		if response.Server.State != state {
+			return fmt.Errorf("server %s is %s, expected %s", rs.Primary.ID, response.Server.State, state)
+		}
+
+		return nil
+	}
+}
+
+func testAccServerConfig(api *mockapi.Server, label string, productCode string, powerState string) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_ssh_key" "test" {
+  label   = "deploy"
+  content = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGv9tSLiDrnk1vrEc5UodKxRPm6d7bLxYPEaxNNWBPmM test@example.com"
+}
+
+resource "sitehost_server" "test" {
+  label        = %q
+  location     = "AKLCITY"
+  product_code = %q
+  image        = "ubuntu-jammy-pvh.amd64"
+  ssh_keys     = [sitehost_ssh_key.test.id]
+  power_state  = %q
+}
+`, label, productCode, powerState)
+}
+
+func testAccServerPowerConfig(api *mockapi.Server, action string, run string) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_server" "test" {
+  label        = "web"
+  location     = "AKLCITY"
+  product_code = "XENLIT"
+  image        = "ubuntu-jammy-pvh.amd64"
+}
+
+resource "sitehost_server_power" "test" {
+  server = sitehost_server.test.name
+  action = %q
+
+  triggers = {
+    run = %q
+  }
+}
+`, action, run)
+}
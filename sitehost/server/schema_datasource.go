@@ -1,6 +1,9 @@
 package server
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
 
 // serverDataSourceSchema is the schema with values for a Server DataSource.
 var serverDataSourceSchema = map[string]*schema.Schema{
@@ -38,4 +41,265 @@ var serverDataSourceSchema = map[string]*schema.Schema{
 		Elem:        &schema.Schema{Type: schema.TypeString},
 		Description: "Each Server is assigned a single public IPv4 address upon creation.",
 	},
+	"location_name": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The name of the location the Server is in.",
+	},
+	"product_name": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The name of the product the Server is on.",
+	},
+	"ram": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The memory of the Server.",
+	},
+	"cores": {
+		Type:        schema.TypeInt,
+		Computed:    true,
+		Description: "The number of CPU cores of the Server.",
+	},
+	"os": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The operating system running on the Server.",
+	},
+	"state": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The state of the Server, e.g. `On` or `Off`.",
+	},
+	"power_state": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Whether the Server is `running` or `stopped`, any other state is shown as SiteHost reports it.",
+	},
+	"created": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "When the Server was created.",
+	},
+	"ipv4_addresses": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The IPv4 addresses assigned to the Server.",
+	},
+	"ipv6_addresses": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The IPv6 addresses assigned to the Server.",
+	},
+	"interfaces": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The network interfaces of the Server.",
+	},
+	"partitions": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The disk partitions of the Server.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The partition name.",
+				},
+				"device": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The device the partition is on.",
+				},
+				"mountpoint": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Where the partition is mounted.",
+				},
+				"size": {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: "The size of the partition in GB.",
+				},
+				"fstype": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The filesystem type of the partition.",
+				},
+				"type": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The partition type.",
+				},
+			},
+		},
+	},
+}
+
+// locationsDataSourceSchema is the schema with values for the Server Locations DataSource.
+var locationsDataSourceSchema = map[string]*schema.Schema{
+	"locations": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The locations Servers can be deployed in.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"code": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The location code, used as the Server `location`.",
+				},
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The location name.",
+				},
+			},
+		},
+	},
+}
+
+// productsDataSourceSchema is the schema with values for the Server Products DataSource.
+var productsDataSourceSchema = map[string]*schema.Schema{
+	"products": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The products Servers can be deployed on.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"code": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The product code, used as the Server `product_code`.",
+				},
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The product name.",
+				},
+				"type": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The product type.",
+				},
+				"cores": {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: "The number of CPU cores.",
+				},
+				"ram": {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: "The memory.",
+				},
+				"disk": {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: "The disk size.",
+				},
+				"price": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The price.",
+				},
+			},
+		},
+	},
+}
+
+// imagesDataSourceSchema is the schema with values for the Server Images DataSource.
+var imagesDataSourceSchema = map[string]*schema.Schema{
+	"images": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The images Servers can be deployed from.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"code": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The image code, used as the Server `image`.",
+				},
+				"label": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The image label.",
+				},
+			},
+		},
+	},
+}
+
+// serversDataSourceSchema is the schema with values for the Servers DataSource.
+var serversDataSourceSchema = map[string]*schema.Schema{
+	"label_regex": {
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringIsValidRegExp,
+		Description:  "Only return Servers with a label matching this regular expression.",
+	},
+	"location": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Only return Servers in this location.",
+	},
+	"product_code": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Only return Servers on this product.",
+	},
+	"security_group": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Only return Servers that use this security group, by its name.",
+	},
+	"servers": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The matching Servers.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The `name` is the ID of the Server.",
+				},
+				"label": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The server's label.",
+				},
+				"location": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The location the Server is in.",
+				},
+				"product_code": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The product code of the Server.",
+				},
+				"state": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The state of the Server.",
+				},
+				"power_state": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Whether the Server is `running` or `stopped`, any other state is shown as SiteHost reports it.",
+				},
+				"ips": {
+					Type:        schema.TypeList,
+					Computed:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+					Description: "The IP addresses assigned to the Server.",
+				},
+			},
+		},
+	},
 }
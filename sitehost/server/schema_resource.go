@@ -1,6 +1,9 @@
 package server
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
 
 // resourceSchema is the schema with values for a Server resource.
 var resourceSchema = map[string]*schema.Schema{
@@ -42,22 +45,178 @@ var resourceSchema = map[string]*schema.Schema{
 		Description: "The product code of the server to be deployed, determining the price and size.",
 	},
 	"securitygroups": {
-		Type:        schema.TypeList,
-		Elem:        &schema.Schema{Type: schema.TypeString},
-		Optional:    true,
-		Description: "The security groups which this server uses.",
+		Type:     schema.TypeList,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+		Optional: true,
+		Description: "The security groups which this server uses, in order. Don't also manage them with `sitehost_server_firewall`, " +
+			"that is only caught when planning both in the same configuration, once the server exists.",
 	},
+	// changing the image replaces the server, unless rebuild_on_image_change is set, see forceNewOnImageChange.
 	"image": {
 		Type:     schema.TypeString,
 		Required: true,
-		ForceNew: true,
 		Description: "An Image ID to deploy the Disk from. The complete list of images ID you can see " +
 			"in our official documentation.",
 	},
+	"rebuild_on_image_change": {
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+		Description: "Reinstall the Server in place when the `image` changes, keeping its name and IP addresses, " +
+			"rather than replacing it. Everything on the Server's disks is lost either way.",
+	},
+	"disk": {
+		Type:     schema.TypeList,
+		Optional: true,
+		Computed: true,
+		Description: "The disks of the Server. Disks can be grown and added, but not shrunk or removed. " +
+			"When no disks are set, all of the Server's disks are read back, so the disks set have to include every disk the Server has.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"label": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.NoZeroValues,
+					Description:  "The label of the disk.",
+				},
+				"size": {
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "The size of the disk in GB.",
+				},
+			},
+		},
+	},
 	"ssh_keys": {
 		Type:        schema.TypeList,
 		Optional:    true,
 		Elem:        &schema.Schema{Type: schema.TypeString},
 		Description: "A list of SSH public keys to deploy for the root user on the newly created Server.",
 	},
+	"location_name": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The name of the location the Server is in.",
+	},
+	"product_name": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The name of the product the Server is on.",
+	},
+	"ram": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The memory of the Server.",
+	},
+	"cores": {
+		Type:        schema.TypeInt,
+		Computed:    true,
+		Description: "The number of CPU cores of the Server.",
+	},
+	"os": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The operating system running on the Server.",
+	},
+	"state": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The state of the Server, e.g. `On` or `Off`.",
+	},
+	"power_state": {
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.StringInSlice([]string{powerStateRunning, powerStateStopped}, false),
+		Description: "Whether the Server is `running` or `stopped`, changing it starts or stops the Server. " +
+			"Any other state is shown as SiteHost reports it.",
+	},
+	"created": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "When the Server was created.",
+	},
+	"ipv4_addresses": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The IPv4 addresses assigned to the Server.",
+	},
+	"ipv6_addresses": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The IPv6 addresses assigned to the Server.",
+	},
+	"interfaces": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The network interfaces of the Server.",
+	},
+	"partitions": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "The disk partitions of the Server.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The partition name.",
+				},
+				"device": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The device the partition is on.",
+				},
+				"mountpoint": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Where the partition is mounted.",
+				},
+				"size": {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: "The size of the partition in GB.",
+				},
+				"fstype": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The filesystem type of the partition.",
+				},
+				"type": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The partition type.",
+				},
+			},
+		},
+	},
+}
+
+// resourcePowerSchema is the schema with values for a Server Power resource.
+var resourcePowerSchema = map[string]*schema.Schema{
+	"server": {
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.NoZeroValues,
+		Description:  "The name of the Server.",
+	},
+	"action": {
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.StringInSlice([]string{powerActionStart, powerActionStop, powerActionReboot}, false),
+		Description:  "The action to take, one of `start`, `stop` or `reboot`.",
+	},
+	"triggers": {
+		Type:        schema.TypeMap,
+		Optional:    true,
+		ForceNew:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "Arbitrary values that, when changed, run the action again.",
+	},
 }
@@ -4,8 +4,8 @@ package sshkey
 import (
 	"context"
 	"fmt"
-	"log"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	sshkey "github.com/sitehostnz/gosh/pkg/api/ssh/key"
@@ -28,6 +28,8 @@ func Resource() *schema.Resource {
 
 // createResource is a function to create a new SSH Key.
 func createResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogSSH)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -72,7 +74,7 @@ func createResource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 		return diagErr
 	}
 
-	log.Printf("[INFO] SSH Key: %s", d.Id())
+	tflog.SubsystemInfo(ctx, helper.LogSSH, "Created SSH key", map[string]any{"key_id": d.Id()})
 
 	return nil
 }
@@ -118,6 +120,9 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Di
 		ID: d.Id(),
 	})
 	if err != nil {
+		if helper.IsNotFound(err) {
+			return helper.RemoveFromState(ctx, d, "SSH Key")
+		}
 		return diag.Errorf("Error retrieving SSH Key: %s", err)
 	}
 
@@ -134,6 +139,8 @@ func readResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Di
 
 // updateResource is a function to update a server.
 func updateResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogSSH)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -142,7 +149,11 @@ func updateResource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 	client := sshkey.New(conf.Client)
 
 	if d.HasChange("label") || d.HasChange("content") || d.HasChange("custom_image_access") {
-		updateKey(ctx, client, d)
+		if diags := updateKey(ctx, client, d); diags.HasError() {
+			return diags
+		}
+
+		tflog.SubsystemInfo(ctx, helper.LogSSH, "Updated SSH key", map[string]any{"key_id": d.Id()})
 	}
 
 	return readResource(ctx, d, meta)
@@ -169,6 +180,8 @@ func updateKey(ctx context.Context, client *sshkey.Client, d *schema.ResourceDat
 
 // deleteResource is a function to delete an SSH Key.
 func deleteResource(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	ctx = helper.LogContext(ctx, helper.LogSSH)
+
 	conf, ok := meta.(*helper.CombinedConfig)
 	if !ok {
 		return diag.Errorf("failed to convert meta object")
@@ -187,5 +200,7 @@ func deleteResource(ctx context.Context, d *schema.ResourceData, meta any) diag.
 		return diag.Errorf("Error deleting SSH Key: %s", resp.Msg)
 	}
 
+	tflog.SubsystemInfo(ctx, helper.LogSSH, "Deleted SSH key", map[string]any{"key_id": d.Id()})
+
 	return nil
 }
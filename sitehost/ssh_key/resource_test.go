@@ -0,0 +1,62 @@
+package sshkey_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sitehostnz/gosh/pkg/api/ssh/key"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/acctest"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/helper"
+	"github.com/sitehostnz/terraform-provider-sitehost/sitehost/mockapi"
+)
+
+const testAccSSHKeyContent = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGv9tSLiDrnk1vrEc5UodKxRPm6d7bLxYPEaxNNWBPmM test@example.com"
+
+func TestAccSSHKey(t *testing.T) {
+	api := acctest.NewAPI(t)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactories,
+		CheckDestroy: acctest.CheckDestroy(t, api, "sitehost_ssh_key", func(ctx context.Context, conf *helper.CombinedConfig, rs *terraform.ResourceState) (bool, error) {
+			_, err := key.New(conf.Client).Get(ctx, key.GetRequest{ID: rs.Primary.ID})
+			return err == nil, err
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSSHKeyConfig(api, "deploy", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("sitehost_ssh_key.test", "id"),
+					resource.TestCheckResourceAttr("sitehost_ssh_key.test", "label", "deploy"),
+					resource.TestCheckResourceAttr("sitehost_ssh_key.test", "content", testAccSSHKeyContent),
+					resource.TestCheckResourceAttr("sitehost_ssh_key.test", "custom_image_access", "false"),
+					resource.TestCheckResourceAttrSet("sitehost_ssh_key.test", "date_added"),
+				),
+			},
+			{
+				Config: testAccSSHKeyConfig(api, "deploy-renamed", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sitehost_ssh_key.test", "label", "deploy-renamed"),
+					resource.TestCheckResourceAttr("sitehost_ssh_key.test", "custom_image_access", "true"),
+				),
+			},
+			{
+				ResourceName:      "sitehost_ssh_key.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccSSHKeyConfig(api *mockapi.Server, label string, customImageAccess bool) string {
+	return acctest.ProviderConfig(api) + fmt.Sprintf(`
+resource "sitehost_ssh_key" "test" {
+  label               = %q
+  content             = %q
+  custom_image_access = %t
+}
+`, label, testAccSSHKeyContent, customImageAccess)
+}